@@ -0,0 +1,90 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/types"
+)
+
+// Is type declared as alias (type A = B)
+func (sym *Symbol) IsAlias() bool {
+	return IsAlias(sym.node())
+}
+
+// Resolve aliases (and type names in expressions) to the target named or built-in type.
+// Aliases of composite types (type A = []B) are resolved to symbol of type expression.
+func (sym *Symbol) Unalias(resolver Resolver) (*Symbol, error) {
+	current := sym
+	for depth := 0; ; depth++ {
+		if depth > maxResolveDepth {
+			return nil, errors.Errorf("too deep alias chain for %v", sym.Name)
+		}
+		if current.BuiltIn || current.TypeParam {
+			return current, nil
+		}
+		switch v := current.Node.(type) {
+		case *ast.TypeSpec:
+			if !v.Assign.IsValid() {
+				return current, nil
+			}
+			current = current.TypeExpr(v.Type)
+		case *ast.ParenExpr:
+			current = current.TypeExpr(v.X)
+		case *ast.Ident:
+			if v.Obj != nil && (v.Obj.Kind == ast.Var || v.Obj.Kind == ast.Con) {
+				// not a type
+				return current, nil
+			}
+			target, err := current.Resolve(resolver)
+			if err != nil {
+				return nil, errors.Wrapf(err, "resolve alias %v", sym.Name)
+			}
+			current = target
+		case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			target, err := current.Resolve(resolver)
+			if err != nil {
+				return nil, errors.Wrapf(err, "resolve alias %v", sym.Name)
+			}
+			current = target
+		default:
+			return current, nil
+		}
+	}
+}
+
+// Fully resolved underlying type: chain of type definitions and aliases is followed until built-in type or
+// type literal (struct, array, map and etc.) that is returned as symbol of the type expression.
+func (sym *Symbol) Underlying(resolver Resolver) (*Symbol, error) {
+	current := sym
+	for depth := 0; ; depth++ {
+		if depth > maxResolveDepth {
+			return nil, errors.Errorf("too deep type definitions chain for %v", sym.Name)
+		}
+		target, err := current.Unalias(resolver)
+		if err != nil {
+			return nil, err
+		}
+		tps, ok := target.Node.(*ast.TypeSpec)
+		if !ok {
+			return target, nil
+		}
+		current = target.TypeExpr(tps.Type)
+	}
+}
+
+// Check that types are identical: aliases are replaced by targets and type literals are compared structurally.
+func (sym *Symbol) Identical(resolver Resolver, other *Symbol) (bool, error) {
+	return identicalTypes(resolver, sym, other)
+}
+
+// symbol that represents type expression (ex: []int, map[string]Item) declared in the file
+func typeExprSymbol(expr ast.Expr, file *File, imp *Import) *Symbol {
+	return &Symbol{
+		Import: imp,
+		File:   file,
+		Node:   expr,
+		Name:   types.ExprString(expr),
+	}
+}
+
+const maxResolveDepth = 128
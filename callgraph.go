@@ -0,0 +1,230 @@
+package symbols
+
+import (
+	"fmt"
+	"go/ast"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Static call graph of functions and methods declared in scanned imports
+type CallGraph struct {
+	Functions []*CallNode // sorted by id and position
+	nodes     map[string]*CallNode
+}
+
+type CallNode struct {
+	Symbol  *Symbol
+	Calls   []*CallNode // called functions and methods sorted by id and position
+	Callers []*CallNode // functions and methods that call the node sorted by id and position
+}
+
+// Node of function or method by canonical id (see Symbol.ID), nil if not found. Declarations which may repeat
+// in package (init functions and blank functions or methods) are identified by id@position (see CallNode.Key).
+func (cg *CallGraph) Node(id string) *CallNode {
+	return cg.nodes[id]
+}
+
+// Write graph in Graphviz DOT format. Functions without calls and callers are omitted.
+func (cg *CallGraph) WriteDOT(w io.Writer) error {
+	if _, err := fmt.Fprintln(w, "digraph calls {"); err != nil {
+		return err
+	}
+	for _, node := range cg.Functions {
+		if len(node.Calls) == 0 && len(node.Callers) == 0 {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "\t%v;\n", strconv.Quote(node.Key())); err != nil {
+			return err
+		}
+	}
+	for _, node := range cg.Functions {
+		for _, callee := range node.Calls {
+			if _, err := fmt.Fprintf(w, "\t%v -> %v;\n", strconv.Quote(node.Key()), strconv.Quote(callee.Key())); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// Build best-effort static call graph from bodies of functions and methods in all scanned imports.
+// Calls of package level functions (including qualified and dot imported), methods of receivers, parameters,
+// variables, fields and results of calls with known types and method expressions are resolved.
+// Calls through interfaces, function values and calls to not scanned packages are skipped.
+func (prj *Project) CallGraph() *CallGraph {
+	cg := &CallGraph{nodes: make(map[string]*CallNode)}
+	var decls []*Symbol
+	for i := range prj.Imports {
+		for _, sym := range prj.Imports[i].declarations() {
+			if sym.IsFunction() {
+				decls = append(decls, sym)
+				cg.node(sym)
+			}
+		}
+	}
+	methods := make(map[string][]*Method)
+	for _, sym := range decls {
+		body := sym.Node.(*ast.FuncDecl).Body
+		if body == nil {
+			continue
+		}
+		caller := cg.node(sym)
+		cr := &callResolver{project: prj, scope: sym, methods: methods}
+		calls := make(map[string]bool)
+		ast.Inspect(body, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			callee := cr.callee(call.Fun)
+			if callee == nil {
+				return true
+			}
+			target := cg.node(callee)
+			if calls[target.Key()] {
+				return true
+			}
+			calls[target.Key()] = true
+			caller.Calls = append(caller.Calls, target)
+			target.Callers = append(target.Callers, caller)
+			return true
+		})
+	}
+	for _, node := range cg.nodes {
+		cg.Functions = append(cg.Functions, node)
+		sortCallNodes(node.Calls)
+		sortCallNodes(node.Callers)
+	}
+	sortCallNodes(cg.Functions)
+	return cg
+}
+
+// Unique key of node: canonical id of symbol, followed by @ and position for init functions and blank
+// functions or methods, which may be declared several times
+func (cn *CallNode) Key() string {
+	sym := cn.Symbol
+	if sym.Name == "_" || sym.Name == "init" && !sym.IsMethod() {
+		return sym.ID() + "@" + sym.Position().String()
+	}
+	return sym.ID()
+}
+
+func (cg *CallGraph) node(sym *Symbol) *CallNode {
+	node := &CallNode{Symbol: sym}
+	if found, ok := cg.nodes[node.Key()]; ok {
+		return found
+	}
+	cg.nodes[node.Key()] = node
+	return node
+}
+
+func sortCallNodes(nodes []*CallNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodes[i].Symbol, nodes[j].Symbol
+		if a.ID() != b.ID() {
+			return a.ID() < b.ID()
+		}
+		return positionLess(a.Position(), b.Position())
+	})
+}
+
+// resolver of called functions and types of expressions in body of function
+type callResolver struct {
+	project *Project
+	scope   *Symbol              // declaration of function
+	methods map[string][]*Method // cache of method sets of pointers to named types
+}
+
+// declaration of called function or method, nil if it can't be determined
+func (cr *callResolver) callee(fun ast.Expr) *Symbol {
+	switch v := unparen(fun).(type) {
+	case *ast.IndexExpr:
+		// instantiation of generic function
+		return cr.callee(v.X)
+	case *ast.IndexListExpr:
+		return cr.callee(v.X)
+	case *ast.Ident:
+		if v.Obj != nil && v.Obj.Kind != ast.Fun {
+			return nil
+		}
+		return cr.function(v.Name)
+	case *ast.SelectorExpr:
+		if pkg, ok := v.X.(*ast.Ident); ok && pkg.Obj == nil {
+			if _, err := cr.project.FindPackageImport(pkg.Name, cr.scope.File); err == nil {
+				return cr.function(pkg.Name + "." + v.Sel.Name)
+			}
+		}
+		if tp := cr.typeName(v.X); tp != nil {
+			// method expression (T.Method or (*T).Method)
+			return cr.method(tp, v.Sel.Name)
+		}
+		return cr.method(cr.exprType(v.X), v.Sel.Name)
+	}
+	return nil
+}
+
+func (cr *callResolver) function(name string) *Symbol {
+	sym, err := cr.project.FindSymbol(name, cr.scope.File)
+	if err != nil || !sym.IsFunction() || sym.IsMethod() {
+		return nil
+	}
+	return sym
+}
+
+// declaration of concrete method of type (pointers are followed)
+func (cr *callResolver) method(tp *Symbol, name string) *Symbol {
+	if tp == nil {
+		return nil
+	}
+	named, err := namedType(cr.project, tp)
+	if err != nil || named.IsInterface() {
+		return nil
+	}
+	methods, ok := cr.methods[named.ID()]
+	if !ok {
+		methods, _ = named.MethodSet(cr.project, true)
+		cr.methods[named.ID()] = methods
+	}
+	for _, m := range methods {
+		if m.Name == name && m.Function != nil && m.Function.scope != nil {
+			return m.Function.scope
+		}
+	}
+	return nil
+}
+
+// named type if expression is a type name
+func (cr *callResolver) typeName(expr ast.Expr) *Symbol {
+	switch v := unparen(expr).(type) {
+	case *ast.StarExpr:
+		return cr.typeName(v.X)
+	case *ast.Ident:
+		if v.Obj != nil && v.Obj.Kind != ast.Typ {
+			return nil
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := v.X.(*ast.Ident); !ok || pkg.Obj != nil {
+			return nil
+		}
+	case *ast.IndexExpr, *ast.IndexListExpr:
+	default:
+		return nil
+	}
+	sym, err := cr.scope.TypeExpr(expr).Resolve(cr.project)
+	if err != nil || !sym.IsType() {
+		return nil
+	}
+	return sym
+}
+
+// type of value expression, nil if it can't be inferred
+func (cr *callResolver) exprType(expr ast.Expr) *Symbol {
+	tp, err := cr.scope.inferType(cr.project, expr, 0, 0)
+	if err != nil {
+		return nil
+	}
+	return tp
+}
@@ -15,6 +15,9 @@ func main() {
 	parser := flags.NewParser(nil, flags.Default)
 	parser.AddCommand("mutate", "mutate struct", "mutate struct and generate mappers for them", &mutateStruct{})
 	parser.AddCommand("methods", "list methods", "list all found methods in all packages", &methods{})
+	parser.AddCommand("calls", "call graph", "print static call graph of scanned packages in DOT format", &calls{})
+	parser.AddCommand("usage", "field usage", "report reads, writes and literal initializations of fields of structs (by names or canonical ids)", &usage{})
+	parser.AddCommand("lookup", "find symbols", "find symbols, methods or fields by canonical ids (import/path.Type.Member) or member paths (pkg.Type.Field.Method)", &lookup{})
 	_, err := parser.Parse()
 	if err != nil {
 		os.Exit(1)
@@ -22,7 +25,7 @@ func main() {
 }
 
 type mutateStruct struct {
-	SourceStruct      string   `long:"source" env:"SOURCE_STRUCT" description:"Name of source struct"`
+	SourceStruct      string   `long:"source" env:"SOURCE_STRUCT" description:"Name of source struct or canonical id (import/path.Type)"`
 	Target            string   `long:"target" env:"TARGET" description:"Name of target struct"`
 	Map               string   `long:"map" env:"MAP" description:"Name of function to map from source to target"`
 	Unmap             string   `long:"unmap" env:"UNMAP" description:"Name of function to map form target to source"`
@@ -33,6 +36,7 @@ type mutateStruct struct {
 	Value             bool     `long:"value" env:"VALUE" description:"Map items passed by value"`
 	ScanLimit         int      `long:"scan-limit" env:"SCAN_LIMIT" description:"Maximum amount of packages to scan. -1 - all" default:"-1"`
 	RequiredByComment string   `long:"required-by-comment" env:"REQUIRED_BY_COMMENT" description:"Add validation method that checks that fields with specified comments are not as default values" default:""`
+	FromDirectives    bool     `long:"from-directives" env:"FROM_DIRECTIVES" description:"Mutate all structs in package marked by //symbols:mutate target=Name [exclude=A,B drop=C map=F unmap=F self-map=F self-unmap=F required=symbols:required value]"`
 }
 
 func (m *mutateStruct) Execute(args []string) error {
@@ -62,6 +66,31 @@ func (m *mutateStruct) Execute(args []string) error {
 
 	} else if len(args) == 1 && args[0] == "X" {
 		return nil
+	} else if m.FromDirectives {
+		err = proj.Package.Symbols(func(sym *symbols.Symbol) error {
+			directive := sym.Directives().Get("symbols:mutate")
+			if directive == nil || !sym.IsStruct() {
+				return nil
+			}
+			if directive.String("target", "") == "" {
+				return fmt.Errorf("%v: target is not defined in %v directive", sym.Name, directive.Name)
+			}
+			return addGeneration(out, &mutateStruct{
+				SourceStruct:      sym.Name,
+				Target:            directive.String("target", ""),
+				Map:               directive.String("map", ""),
+				Unmap:             directive.String("unmap", ""),
+				SelfMap:           directive.String("self-map", ""),
+				SelfUnmap:         directive.String("self-unmap", ""),
+				Exclude:           directive.List("exclude"),
+				Drop:              directive.List("drop"),
+				Value:             directive.Has("value"),
+				RequiredByComment: directive.String("required", ""),
+			}, proj)
+		})
+		if err != nil {
+			return err
+		}
 	} else {
 		err = addGeneration(out, m, proj)
 		if err != nil {
@@ -72,7 +101,7 @@ func (m *mutateStruct) Execute(args []string) error {
 }
 
 func addGeneration(out *jen.File, m *mutateStruct, proj *symbols.Project) error {
-	sym, err := proj.FindLocalSymbol(m.SourceStruct)
+	sym, err := findSymbol(proj, m.SourceStruct)
 	if err != nil {
 		return err
 	}
@@ -83,7 +112,20 @@ func addGeneration(out *jen.File, m *mutateStruct, proj *symbols.Project) error
 		return err
 	}
 
-	mutated, err := coder.MutateStruct(sym, append(m.Exclude, m.Drop...))
+	exclude := append(append([]string{}, m.Exclude...), m.Drop...)
+	if sym.Import.Import != proj.Package.Import {
+		// unexported fields of other packages can't be mapped
+		fields, err := sym.Fields(proj)
+		if err != nil {
+			return err
+		}
+		for _, f := range fields {
+			if !f.Exported() {
+				exclude = append(exclude, f.Name)
+			}
+		}
+	}
+	mutated, err := coder.MutateStruct(sym, exclude)
 	if err != nil {
 		return err
 	}
@@ -163,3 +205,108 @@ func (m *methods) Execute([]string) error {
 	}
 	return nil
 }
+
+// find symbol by local name or by canonical id
+func findSymbol(proj *symbols.Project, name string) (*symbols.Symbol, error) {
+	if !strings.ContainsAny(name, "./") {
+		return proj.FindLocalSymbol(name)
+	}
+	sel, err := findMember(proj, name)
+	if err != nil {
+		return nil, err
+	}
+	if sel.Method != nil || sel.Field != nil {
+		return nil, fmt.Errorf("%v is not a type", name)
+	}
+	return sel.Symbol, nil
+}
+
+type lookup struct {
+	ScanLimit int `long:"scan-limit" env:"SCAN_LIMIT" description:"Maximum amount of packages to scan. -1 - all" default:"-1"`
+}
+
+// find symbol or member by canonical id (import/path.Type.Field) or by path relative to the package
+// (Type.Field.Method, pkg.Type.Field)
+func findMember(proj *symbols.Project, path string) (*symbols.Selection, error) {
+	if strings.Contains(path, "/") {
+		return proj.Lookup(path)
+	}
+	var lastErr error = fmt.Errorf("no files in %v", proj.Package.Import)
+	for _, file := range proj.Package.Files {
+		sel, err := proj.FindMember(path, file)
+		if err == nil {
+			return sel, nil
+		}
+		lastErr = err
+	}
+	if sel, err := proj.Lookup(path); err == nil {
+		return sel, nil
+	}
+	return nil, lastErr
+}
+
+func (m *lookup) Execute(args []string) error {
+	proj, err := symbols.ProjectByDir(".", m.ScanLimit)
+	if err != nil {
+		return err
+	}
+	for _, id := range args {
+		sel, err := findMember(proj, id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case sel.Method != nil:
+			fmt.Println("method", sel.ID())
+		case sel.Field != nil:
+			fmt.Println("field", sel.ID(), sel.Type.ID())
+		default:
+			fmt.Println(sel.Symbol.Kind(), sel.ID())
+		}
+	}
+	return nil
+}
+
+type calls struct {
+	ScanLimit int `long:"scan-limit" env:"SCAN_LIMIT" description:"Maximum amount of packages to scan. -1 - all" default:"1"`
+}
+
+func (m *calls) Execute([]string) error {
+	proj, err := symbols.ProjectByDir(".", m.ScanLimit)
+	if err != nil {
+		return err
+	}
+	return proj.CallGraph().WriteDOT(os.Stdout)
+}
+
+type usage struct {
+	ScanLimit int `long:"scan-limit" env:"SCAN_LIMIT" description:"Maximum amount of packages to scan. -1 - all" default:"1"`
+}
+
+func (m *usage) Execute(args []string) error {
+	proj, err := symbols.ProjectByDir(".", m.ScanLimit)
+	if err != nil {
+		return err
+	}
+	for _, name := range args {
+		sym, err := findSymbol(proj, name)
+		if err != nil {
+			return err
+		}
+		usages, err := proj.FieldUsage(sym)
+		if err != nil {
+			return err
+		}
+		for _, u := range usages {
+			var state string
+			switch {
+			case u.Unused():
+				state = "unused"
+			case u.WriteOnly():
+				state = "write-only"
+			}
+			fmt.Println(u.Field.ID(), len(u.Reads), len(u.Writes), len(u.Literals), state)
+		}
+	}
+	return nil
+}
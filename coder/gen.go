@@ -5,42 +5,36 @@ import (
 	"github.com/iancoleman/strcase"
 	"github.com/pkg/errors"
 	"github.com/reddec/symbols"
-	"go/ast"
 	"sort"
 	"strings"
 )
 
-func generatePrefix(expr ast.Expr) jen.Code {
-	if v, ok := expr.(*ast.StarExpr); ok {
-		return jen.Op("*").Add(generatePrefix(v.X))
-	}
-	if v, ok := expr.(*ast.ArrayType); ok {
-		return jen.Index().Add(generatePrefix(v.Elt))
-	}
-	return jen.Empty()
-}
-
-func generateType(tp *symbols.Symbol) jen.Code {
-	if tp.BuiltIn {
-		return jen.Id(tp.Name)
-	}
-	return jen.Qual(tp.Import.Import, tp.Name)
-}
-
 func GenerateStruct(sym *symbols.Symbol, resolver symbols.Resolver) (jen.Code, error) {
 	fields, err := sym.Fields(resolver)
 	if err != nil {
 		return nil, err
 	}
-	return jen.Type().Id(sym.Name).StructFunc(func(st *jen.Group) {
-		for _, field := range fields {
-			f := st.Id(field.Name).Add(generatePrefix(field.RawType)).Add(generateType(field.Type)).Tag(field.Tags)
-			comment := field.Comment()
-			if comment != "" {
-				f.Comment(comment)
-			}
+	typeParams, err := generateTypeParams(sym.TypeParams(), resolver)
+	if err != nil {
+		return nil, err
+	}
+	var items []jen.Code
+	for _, field := range fields {
+		tp, err := generateTypeExpr(field.TypeExpr(), resolver)
+		if err != nil {
+			return nil, errors.Wrapf(err, "type of field %v", field.Name)
 		}
-	}), nil
+		f := jen.Id(field.Name).Add(tp)
+		if tags := field.Tags.Literal(); tags != "" {
+			f.Op(tags)
+		}
+		comment := field.Comment()
+		if comment != "" {
+			f.Comment(comment)
+		}
+		items = append(items, f)
+	}
+	return jen.Type().Id(sym.Name).Add(typeParams).Struct(items...), nil
 }
 
 func GenerateStructMapper(source, target *symbols.Symbol, resolver symbols.Resolver, funcName string, ref bool) (jen.Code, error) {
@@ -57,13 +51,20 @@ func GenerateStructMapper(source, target *symbols.Symbol, resolver symbols.Resol
 	if ref {
 		mod = jen.Op("*")
 	}
-
-	return jen.Func().Id(funcName).ParamsFunc(func(params *jen.Group) {
-		params.Id(srcName).Add(mod).Add(generateType(source))
-		for _, f := range unknownField {
-			params.Id(strcase.ToLowerCamel(f.Name)).Add(generateType(f.Type))
+	typeParams, err := generateTypeParams(mergeTypeParams(source, target), resolver)
+	if err != nil {
+		return nil, err
+	}
+	srcType, targetType, params, err := prepareMapperTypes(source, target, unknownField, resolver)
+	if err != nil {
+		return nil, err
+	}
+	return jen.Func().Id(funcName).Add(typeParams).ParamsFunc(func(group *jen.Group) {
+		group.Id(srcName).Add(mod).Add(srcType)
+		for _, p := range params {
+			group.Add(p)
 		}
-	}).Add(mod).Add(generateType(target)).Add(mapStruct(targetName, srcName, exists, tFields, target, ref)), nil
+	}).Add(mod).Add(targetType).Add(mapStruct(targetName, srcName, exists, tFields, targetType, ref)), nil
 }
 func GenerateSelfStructMapper(source, target *symbols.Symbol, resolver symbols.Resolver, funcName string, ref bool) (jen.Code, error) {
 	exists, tFields, unknownField, err := prepareMapStruct(source, target, resolver)
@@ -79,15 +80,44 @@ func GenerateSelfStructMapper(source, target *symbols.Symbol, resolver symbols.R
 	if ref {
 		mod = jen.Op("*")
 	}
+	// methods can't declare own type parameters, so all of them should come from receiver
+	if len(mergeTypeParams(source, target)) != len(source.TypeParams()) {
+		return nil, errors.Errorf("type parameters of %v should be declared by %v", target.Name, source.Name)
+	}
+	srcType, targetType, params, err := prepareMapperTypes(source, target, unknownField, resolver)
+	if err != nil {
+		return nil, err
+	}
+	return jen.Func().Parens(jen.Id(srcName).Add(mod).Add(srcType)).Id(funcName).Params(params...).Add(mod).Add(targetType).Add(mapStruct(targetName, srcName, exists, tFields, targetType, ref)), nil
+}
 
-	return jen.Func().Parens(jen.Id(srcName).Add(mod).Add(generateType(source))).Id(funcName).ParamsFunc(func(params *jen.Group) {
-		for _, f := range unknownField {
-			params.Id(strcase.ToLowerCamel(f.Name)).Add(generateType(f.Type))
+// types of source, target and parameters for unknown fields
+func prepareMapperTypes(source, target *symbols.Symbol, unknownField []*symbols.Field, resolver symbols.Resolver) (jen.Code, jen.Code, []jen.Code, error) {
+	srcType, err := generateType(source, resolver)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	targetType, err := generateType(target, resolver)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var params []jen.Code
+	for _, f := range unknownField {
+		tp, err := generateTypeExpr(f.TypeExpr(), resolver)
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "type of field %v", f.Name)
 		}
-	}).Add(mod).Add(generateType(target)).Add(mapStruct(targetName, srcName, exists, tFields, target, ref)), nil
+		params = append(params, jen.Id(strcase.ToLowerCamel(f.Name)).Add(tp))
+	}
+	return srcType, targetType, params, nil
 }
 
 func prepareMapStruct(source, target *symbols.Symbol, resolver symbols.Resolver) (map[string]*symbols.Field, []*symbols.Field, []*symbols.Field, error) {
+	unified, err := unifyTypeParams(source, target)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	source, target = unified[0], unified[1]
 	sFields, err := source.Fields(resolver)
 	if err != nil {
 		return nil, nil, nil, err
@@ -96,24 +126,42 @@ func prepareMapStruct(source, target *symbols.Symbol, resolver symbols.Resolver)
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	if !samePackage(source, target) {
+		// generated code can't access unexported fields of both structs
+		sFields, tFields = symbols.ExportedFields(sFields), symbols.ExportedFields(tFields)
+	}
 	var exists = map[string]*symbols.Field{}
 	for _, f := range sFields {
 		exists[f.Name] = f
 	}
 	var unknownField []*symbols.Field
 	for _, f := range tFields {
-		if sf, ok := exists[f.Name]; !ok {
+		sf, ok := exists[f.Name]
+		if !ok {
 			unknownField = append(unknownField, f)
-		} else if !sf.Type.Equal(f.Type) {
+			continue
+		}
+		same, err := sf.TypeExpr().Identical(resolver, f.TypeExpr())
+		if err != nil {
+			return nil, nil, nil, errors.Wrapf(err, "compare type of field %v", f.Name)
+		}
+		if !same {
 			return nil, nil, nil, errors.Errorf("field %v has different type in source and target struct", f.Name)
 		}
 	}
 	return exists, tFields, unknownField, nil
 }
 
-func mapStruct(targetName string, srcName string, exists map[string]*symbols.Field, tFields []*symbols.Field, target *symbols.Symbol, ref bool) jen.Code {
+func samePackage(a, b *symbols.Symbol) bool {
+	if a.Import == nil || b.Import == nil {
+		return a.Import == b.Import
+	}
+	return a.Import.Import == b.Import.Import
+}
+
+func mapStruct(targetName string, srcName string, exists map[string]*symbols.Field, tFields []*symbols.Field, targetType jen.Code, ref bool) jen.Code {
 	return jen.BlockFunc(func(group *jen.Group) {
-		group.Var().Id(targetName).Add(generateType(target))
+		group.Var().Id(targetName).Add(targetType)
 		for _, f := range tFields {
 			if exists[f.Name] != nil {
 				group.Id(targetName).Dot(f.Name).Op("=").Id(srcName).Dot(f.Name)
@@ -128,6 +176,8 @@ func mapStruct(targetName string, srcName string, exists map[string]*symbols.Fie
 		}
 	})
 }
+
+// Validation of fields which comments contain text or which have directive with the same name (symbols:required)
 func GenerateValidationByComment(sym *symbols.Symbol, resolver symbols.Resolver, requiredComment string) (jen.Code, error) {
 	sFields, err := sym.Fields(resolver)
 	if err != nil {
@@ -135,7 +185,7 @@ func GenerateValidationByComment(sym *symbols.Symbol, resolver symbols.Resolver,
 	}
 	var fields []string
 	for _, f := range sFields {
-		if strings.Contains(f.Comment(), requiredComment) {
+		if strings.Contains(f.Comment(), requiredComment) || f.Directives().Has(requiredComment) {
 			fields = append(fields, f.Name)
 		}
 	}
@@ -149,20 +199,45 @@ func GenerateValidation(sym *symbols.Symbol, resolver symbols.Resolver, required
 	}
 	sort.Strings(requiredField)
 	var reallyRequired []string
+	var fields = make(map[string]*symbols.Field)
 	for _, f := range sFields {
 		if i := sort.SearchStrings(requiredField, f.Name); i < len(requiredField) && requiredField[i] == f.Name {
 			reallyRequired = append(reallyRequired, f.Name)
+			fields[f.Name] = f
+		}
+	}
+	symType, err := generateType(sym, resolver)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		conditions []jen.Code
+		useDefault bool
+	)
+	for _, field := range reallyRequired {
+		f := fields[field]
+		zero, err := f.TypeExpr().Zero(resolver)
+		if err != nil {
+			return nil, errors.Wrapf(err, "zero value of field %v", field)
 		}
+		cond, err := generateIsZero(jen.Id("self").Dot(field), jen.Id("byDefault").Dot(field), zero, resolver)
+		if err != nil {
+			return nil, errors.Wrapf(err, "check of field %v", field)
+		}
+		conditions = append(conditions, cond)
+		useDefault = useDefault || usesZeroValue(zero)
 	}
-	return jen.Func().Parens(jen.Id("self").Op("*").Id(sym.Name)).Id("Validate").Params().Error().BlockFunc(func(group *jen.Group) {
+	return jen.Func().Parens(jen.Id("self").Op("*").Id(sym.Name).Add(generateTypeParamsNames(sym.TypeParams()))).Id("Validate").Params().Error().BlockFunc(func(group *jen.Group) {
 		if len(reallyRequired) == 0 {
 			group.Return(jen.Nil())
 			return
 		}
-		group.Var().Id("byDefault").Add(generateType(sym))
+		if useDefault {
+			group.Var().Id("byDefault").Add(symType)
+		}
 		group.Var().Id("errorsTxt").Index().String()
-		for _, field := range reallyRequired {
-			group.If(jen.Id("self").Dot(field).Op("==").Id("byDefault").Dot(field)).BlockFunc(func(ifDef *jen.Group) {
+		for i, field := range reallyRequired {
+			group.If(conditions[i]).BlockFunc(func(ifDef *jen.Group) {
 				ifDef.Id("errorsTxt").Op("=").Append(jen.Id("errorsTxt"), jen.Lit(field+" is not defined"))
 			})
 		}
@@ -172,3 +247,65 @@ func GenerateValidation(sym *symbols.Symbol, resolver symbols.Resolver, required
 		group.Return(jen.Qual("github.com/pkg/errors", "New").Call(jen.Qual("strings", "Join").Call(jen.Id("errorsTxt"), jen.Lit(", "))))
 	}), nil
 }
+
+// expression that checks that value is zero, comparable values are compared with the same path in zero value
+func generateIsZero(value, zeroValue jen.Code, zero *symbols.Zero, resolver symbols.Resolver) (jen.Code, error) {
+	switch zero.Check {
+	case symbols.ZeroCompare:
+		return jen.Add(value).Op("==").Add(zeroValue), nil
+	case symbols.ZeroNil:
+		return jen.Add(value).Op("==").Nil(), nil
+	case symbols.ZeroLen:
+		return jen.Len(value).Op("==").Lit(0), nil
+	case symbols.ZeroFields:
+		cond := jen.Null()
+		for i, f := range zero.Fields {
+			check, err := generateIsZero(jen.Add(value).Dot(f.Name), jen.Add(zeroValue).Dot(f.Name), f.Zero, resolver)
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %v", f.Name)
+			}
+			if i > 0 {
+				cond.Op("&&")
+			}
+			cond.Add(check)
+		}
+		if len(zero.Fields) == 0 {
+			return jen.True(), nil
+		}
+		return cond, nil
+	case symbols.ZeroElements:
+		elemType, err := generateTypeExpr(zero.Elem.Type, resolver)
+		if err != nil {
+			return nil, err
+		}
+		check, err := generateIsZero(jen.Id("item"), jen.Id("zero"), zero.Elem, resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Func().Params().Bool().BlockFunc(func(group *jen.Group) {
+			if usesZeroValue(zero.Elem) {
+				group.Var().Id("zero").Add(elemType)
+			}
+			group.For(jen.List(jen.Id("_"), jen.Id("item")).Op(":=").Range().Add(value)).Block(
+				jen.If(jen.Op("!").Parens(check)).Block(jen.Return(jen.False())),
+			)
+			group.Return(jen.True())
+		}).Call(), nil
+	}
+	return nil, errors.Errorf("unknown zero check of %v", zero.Type.Name)
+}
+
+// check compares value with zero value of type
+func usesZeroValue(zero *symbols.Zero) bool {
+	switch zero.Check {
+	case symbols.ZeroCompare:
+		return true
+	case symbols.ZeroFields:
+		for _, f := range zero.Fields {
+			if usesZeroValue(f.Zero) {
+				return true
+			}
+		}
+	}
+	return false
+}
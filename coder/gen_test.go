@@ -2,9 +2,11 @@ package coder
 
 import (
 	"bytes"
+	"errors"
 	"github.com/dave/jennifer/jen"
 	"github.com/reddec/symbols"
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
 )
 
@@ -80,6 +82,11 @@ func TestMutateStruct(t *testing.T) {
 	assert.NoError(t, err, "render")
 
 	assert.Equal(t, sample, buf.String(), "compare generated")
+
+	constant, err := sym.FindSymbol("sample", sym.Package.FindFile("gen_test.go"))
+	assert.NoError(t, err, "find constant")
+	_, err = MutateStruct(constant, nil)
+	assert.True(t, errors.Is(err, symbols.ErrNotStruct))
 }
 
 type A struct {
@@ -122,6 +129,31 @@ func TestGenerateStructMapper(t *testing.T) {
 	assert.Equal(t, sample3, buf.String(), "compare generated")
 }
 
+type UserID = int64
+
+type UserB struct {
+	UserID  UserID
+	Request string
+}
+
+func TestGenerateStructMapperAlias(t *testing.T) {
+	out := jen.NewFile("main")
+	sym, err := symbols.ProjectByDir(".", symbols.All)
+	assert.NoError(t, err, "parse")
+	userA, err := sym.FindSymbol("UserA", sym.Package.FindFile("gen_test.go"))
+	assert.NoError(t, err, "find struct UserA")
+	userB, err := sym.FindSymbol("UserB", sym.Package.FindFile("gen_test.go"))
+	assert.NoError(t, err, "find struct UserB")
+
+	generated, err := GenerateStructMapper(userA, userB, sym, "MapB", true)
+	assert.NoError(t, err, "generate")
+	out.Add(generated)
+	buf := &bytes.Buffer{}
+	err = out.Render(buf)
+	assert.NoError(t, err, "render")
+	assert.True(t, strings.Contains(buf.String(), "destUserB.UserID = srcUserA.UserID"), "alias field is mapped")
+}
+
 const sampleRequired = `package main
 
 import (
@@ -158,3 +190,126 @@ func TestGenerateValidation(t *testing.T) {
 	assert.NoError(t, err, "render")
 	assert.Equal(t, sampleRequired, buf.String(), "compare generated")
 }
+
+const sampleGeneric = `package generics
+
+type Pair[K comparable, V Number] struct {
+	Key   K
+	Value V
+}
+
+func MapPage[T any](srcPage *Page[T], total int) *PageDTO[T] {
+	var destPageDTO PageDTO[T]
+	destPageDTO.Items = srcPage.Items
+	destPageDTO.Total = total
+	return &destPageDTO
+}
+`
+
+func TestGenerateGeneric(t *testing.T) {
+	proj, err := symbols.ProjectByDir("../testdata/generics", 1)
+	assert.NoError(t, err, "parse")
+	out := jen.NewFilePathName(proj.Package.Import, "generics")
+	pair, err := proj.FindLocalSymbol("Pair")
+	assert.NoError(t, err, "find struct Pair")
+	generated, err := GenerateStruct(pair, proj)
+	assert.NoError(t, err, "generate struct")
+	out.Add(generated)
+
+	page, err := proj.FindLocalSymbol("Page")
+	assert.NoError(t, err, "find struct Page")
+	pageDTO, err := proj.FindLocalSymbol("PageDTO")
+	assert.NoError(t, err, "find struct PageDTO")
+	generated, err = GenerateStructMapper(page, pageDTO, proj, "MapPage", true)
+	assert.NoError(t, err, "generate mapper")
+	out.Add(generated)
+
+	buf := &bytes.Buffer{}
+	err = out.Render(buf)
+	assert.NoError(t, err, "render")
+	assert.Equal(t, sampleGeneric, buf.String(), "compare generated")
+}
+
+const sampleForeignMapper = `package visibility
+
+import dto "example.com/dto"
+
+func MapAccount(srcAccount *dto.Account) *Account {
+	var destAccount Account
+	destAccount.ID = srcAccount.ID
+	destAccount.Name = srcAccount.Name
+	return &destAccount
+}
+`
+
+func TestGenerateStructMapperForeign(t *testing.T) {
+	proj, err := symbols.ProjectByDir("../testdata/visibility", 1)
+	assert.NoError(t, err, "parse")
+	account, err := proj.FindLocalSymbol("Account")
+	assert.NoError(t, err, "find struct Account")
+	// the same struct declared in other package
+	foreign := *account.Import
+	foreign.Import = "example.com/dto"
+	source := account.WithNode(account.Node)
+	source.Import = &foreign
+
+	out := jen.NewFilePathName(proj.Package.Import, "visibility")
+	generated, err := GenerateStructMapper(source, account, proj, "MapAccount", true)
+	assert.NoError(t, err, "generate mapper")
+	out.Add(generated)
+	buf := &bytes.Buffer{}
+	err = out.Render(buf)
+	assert.NoError(t, err, "render")
+	assert.Equal(t, sampleForeignMapper, buf.String(), "compare generated")
+}
+
+const sampleZeroValidation = `package zero
+
+import (
+	errors "github.com/pkg/errors"
+	"strings"
+)
+
+func (self *Profile) Validate() error {
+	var byDefault Profile
+	var errorsTxt []string
+	if self.Name == byDefault.Name {
+		errorsTxt = append(errorsTxt, "Name is not defined")
+	}
+	if len(self.Tags) == 0 {
+		errorsTxt = append(errorsTxt, "Tags is not defined")
+	}
+	if self.Owner == nil {
+		errorsTxt = append(errorsTxt, "Owner is not defined")
+	}
+	if func() bool {
+		for _, item := range self.Grid {
+			if !(len(item) == 0) {
+				return false
+			}
+		}
+		return true
+	}() {
+		errorsTxt = append(errorsTxt, "Grid is not defined")
+	}
+	if errorsTxt == nil {
+		return nil
+	}
+	return errors.New(strings.Join(errorsTxt, ", "))
+}
+`
+
+func TestGenerateValidationNotComparable(t *testing.T) {
+	proj, err := symbols.ProjectByDir("../testdata/zero", 1)
+	assert.NoError(t, err, "parse")
+	profile, err := proj.FindLocalSymbol("Profile")
+	assert.NoError(t, err, "find struct Profile")
+	out := jen.NewFilePathName(proj.Package.Import, "zero")
+	generated, err := GenerateValidation(profile, proj, []string{"Name", "Tags", "Owner", "Grid"})
+	assert.NoError(t, err, "generate")
+	out.Add(generated)
+	buf := &bytes.Buffer{}
+	err = out.Render(buf)
+	assert.NoError(t, err, "render")
+	assert.Equal(t, sampleZeroValidation, buf.String(), "compare generated")
+}
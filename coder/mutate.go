@@ -10,13 +10,15 @@ import (
 
 // Mutate struct
 func MutateStruct(symStruct *symbols.Symbol, excludeFields []string) (*symbols.Symbol, error) {
-	ok := symStruct.IsStruct()
+	oldRoot, ok := symStruct.Node.(*ast.TypeSpec)
 	if !ok {
-		return nil, errors.New("is not struct")
+		return nil, errors.Wrap(symbols.ErrNotStruct, symStruct.Name)
+	}
+	st, ok := oldRoot.Type.(*ast.StructType)
+	if !ok {
+		return nil, errors.Wrap(symbols.ErrNotStruct, symStruct.Name)
 	}
 	excluded := toSet(excludeFields)
-	oldRoot := symStruct.Node.(*ast.TypeSpec)
-	st := oldRoot.Type.(*ast.StructType)
 
 	cp := make([]*ast.Field, len(st.Fields.List))
 	copy(cp, st.Fields.List)
@@ -0,0 +1,271 @@
+package coder
+
+import (
+	"github.com/dave/jennifer/jen"
+	"github.com/pkg/errors"
+	"github.com/reddec/symbols"
+	"go/ast"
+	"go/types"
+)
+
+// generate reference to named, built-in type or type parameter
+func generateType(tp *symbols.Symbol, resolver symbols.Resolver) (jen.Code, error) {
+	if tp.BuiltIn || tp.TypeParam {
+		return jen.Id(tp.Name), nil
+	}
+	if !tp.IsType() {
+		return generateTypeExpr(tp, resolver)
+	}
+	code := jen.Qual(tp.Import.Import, tp.Name)
+	if len(tp.TypeArgs) > 0 {
+		var args []jen.Code
+		for _, arg := range tp.TypeArgs {
+			argCode, err := generateTypeExpr(arg, resolver)
+			if err != nil {
+				return nil, errors.Wrapf(err, "type argument of %v", tp.Name)
+			}
+			args = append(args, argCode)
+		}
+		code.Index(jen.List(args...))
+	} else if tp.IsGeneric() {
+		// generic type in scope of own type parameters
+		code.Add(generateTypeParamsNames(tp.TypeParams()))
+	}
+	return code, nil
+}
+
+// generate type expression with qualified names
+func generateTypeExpr(tp *symbols.Symbol, resolver symbols.Resolver) (jen.Code, error) {
+	if tp.BuiltIn || tp.TypeParam || tp.IsType() {
+		return generateType(tp, resolver)
+	}
+	switch v := tp.Node.(type) {
+	case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		named, err := tp.Resolve(resolver)
+		if err != nil {
+			return nil, err
+		}
+		if named == tp {
+			return nil, errors.Errorf("%v is not a type", tp.Name)
+		}
+		return generateType(named, resolver)
+	case *ast.ParenExpr:
+		return generateTypeExpr(tp.TypeExpr(v.X), resolver)
+	case *ast.StarExpr:
+		x, err := generateTypeExpr(tp.TypeExpr(v.X), resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Op("*").Add(x), nil
+	case *ast.Ellipsis:
+		elt, err := generateTypeExpr(tp.TypeExpr(v.Elt), resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Op("...").Add(elt), nil
+	case *ast.ArrayType:
+		elt, err := generateTypeExpr(tp.TypeExpr(v.Elt), resolver)
+		if err != nil {
+			return nil, err
+		}
+		if v.Len == nil {
+			return jen.Index().Add(elt), nil
+		}
+		return jen.Index(jen.Op(types.ExprString(v.Len))).Add(elt), nil
+	case *ast.MapType:
+		key, err := generateTypeExpr(tp.TypeExpr(v.Key), resolver)
+		if err != nil {
+			return nil, err
+		}
+		value, err := generateTypeExpr(tp.TypeExpr(v.Value), resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Map(key).Add(value), nil
+	case *ast.ChanType:
+		value, err := generateTypeExpr(tp.TypeExpr(v.Value), resolver)
+		if err != nil {
+			return nil, err
+		}
+		switch v.Dir {
+		case ast.SEND:
+			return jen.Chan().Op("<-").Add(value), nil
+		case ast.RECV:
+			return jen.Op("<-").Chan().Add(value), nil
+		default:
+			return jen.Chan().Add(value), nil
+		}
+	case *ast.FuncType:
+		signature, err := generateSignature(tp, v, resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Func().Add(signature), nil
+	case *ast.StructType:
+		fields, err := generateFieldList(tp, v.Fields, resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Struct(fields...), nil
+	case *ast.InterfaceType:
+		var methods []jen.Code
+		for _, f := range v.Methods.List {
+			if len(f.Names) == 0 {
+				embedded, err := generateTypeExpr(tp.TypeExpr(f.Type), resolver)
+				if err != nil {
+					return nil, err
+				}
+				methods = append(methods, embedded)
+				continue
+			}
+			signature, err := generateSignature(tp, f.Type.(*ast.FuncType), resolver)
+			if err != nil {
+				return nil, err
+			}
+			methods = append(methods, jen.Id(f.Names[0].Name).Add(signature))
+		}
+		return jen.Interface(methods...), nil
+	case *ast.BinaryExpr:
+		// union of type set
+		x, err := generateTypeExpr(tp.TypeExpr(v.X), resolver)
+		if err != nil {
+			return nil, err
+		}
+		y, err := generateTypeExpr(tp.TypeExpr(v.Y), resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Add(x).Op(v.Op.String()).Add(y), nil
+	case *ast.UnaryExpr:
+		// approximation element (~int)
+		x, err := generateTypeExpr(tp.TypeExpr(v.X), resolver)
+		if err != nil {
+			return nil, err
+		}
+		return jen.Op(v.Op.String()).Add(x), nil
+	}
+	expr, _ := tp.Node.(ast.Expr)
+	return nil, &symbols.UnsupportedTypeError{Expr: expr, Pos: tp.Position()}
+}
+
+// parameters and results of function type
+func generateSignature(scope *symbols.Symbol, fn *ast.FuncType, resolver symbols.Resolver) (jen.Code, error) {
+	params, err := generateFieldList(scope, fn.Params, resolver)
+	if err != nil {
+		return nil, err
+	}
+	results, err := generateFieldList(scope, fn.Results, resolver)
+	if err != nil {
+		return nil, err
+	}
+	code := jen.Params(params...)
+	switch {
+	case len(results) == 1 && (fn.Results.List[0].Names == nil):
+		code.Add(results[0])
+	case len(results) > 0:
+		code.Params(results...)
+	}
+	return code, nil
+}
+
+// fields, parameters or results
+func generateFieldList(scope *symbols.Symbol, list *ast.FieldList, resolver symbols.Resolver) ([]jen.Code, error) {
+	if list == nil {
+		return nil, nil
+	}
+	var ans []jen.Code
+	for _, f := range list.List {
+		tp, err := generateTypeExpr(scope.TypeExpr(f.Type), resolver)
+		if err != nil {
+			return nil, err
+		}
+		var names []jen.Code
+		for _, name := range f.Names {
+			names = append(names, jen.Id(name.Name))
+		}
+		var code *jen.Statement
+		if len(names) == 0 {
+			code = jen.Add(tp)
+		} else {
+			code = jen.List(names...).Add(tp)
+		}
+		if f.Tag != nil {
+			code.Op(f.Tag.Value)
+		}
+		ans = append(ans, code)
+	}
+	return ans, nil
+}
+
+// type parameters declaration with constraints ([K comparable, V any])
+func generateTypeParams(params []*symbols.Symbol, resolver symbols.Resolver) (jen.Code, error) {
+	if len(params) == 0 {
+		return jen.Null(), nil
+	}
+	var items []jen.Code
+	for _, param := range params {
+		var constraint jen.Code = jen.Id("any")
+		if c := param.Constraint(); c != nil {
+			code, err := generateTypeExpr(c, resolver)
+			if err != nil {
+				return nil, errors.Wrapf(err, "constraint of %v", param.Name)
+			}
+			constraint = code
+		}
+		items = append(items, jen.Id(param.Name).Add(constraint))
+	}
+	return jen.Index(jen.List(items...)), nil
+}
+
+// type parameters as arguments ([K, V])
+func generateTypeParamsNames(params []*symbols.Symbol) jen.Code {
+	if len(params) == 0 {
+		return jen.Null()
+	}
+	var items []jen.Code
+	for _, param := range params {
+		items = append(items, jen.Id(param.Name))
+	}
+	return jen.Index(jen.List(items...))
+}
+
+// merge type parameters by names
+func mergeTypeParams(syms ...*symbols.Symbol) []*symbols.Symbol {
+	var ans []*symbols.Symbol
+	var seen = make(map[string]bool)
+	for _, sym := range syms {
+		for _, param := range sym.TypeParams() {
+			if seen[param.Name] {
+				continue
+			}
+			seen[param.Name] = true
+			ans = append(ans, param)
+		}
+	}
+	return ans
+}
+
+// instantiate generic symbols by the same type parameters (matched by names) so fields could be compared
+func unifyTypeParams(syms ...*symbols.Symbol) ([]*symbols.Symbol, error) {
+	var params = make(map[string]*symbols.Symbol)
+	for _, param := range mergeTypeParams(syms...) {
+		params[param.Name] = param
+	}
+	var ans []*symbols.Symbol
+	for _, sym := range syms {
+		if !sym.IsGeneric() || len(sym.TypeArgs) > 0 {
+			ans = append(ans, sym)
+			continue
+		}
+		var args []*symbols.Symbol
+		for _, param := range sym.TypeParams() {
+			args = append(args, params[param.Name])
+		}
+		inst, err := sym.Instantiate(args...)
+		if err != nil {
+			return nil, err
+		}
+		ans = append(ans, inst)
+	}
+	return ans, nil
+}
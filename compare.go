@@ -0,0 +1,192 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/types"
+	"sort"
+)
+
+// check that types (named or type expressions) are identical
+func identicalTypes(resolver Resolver, aSym, bSym *Symbol) (bool, error) {
+	aSym, err := aSym.Unalias(resolver)
+	if err != nil {
+		return false, err
+	}
+	bSym, err = bSym.Unalias(resolver)
+	if err != nil {
+		return false, err
+	}
+	aIsExpr, bIsExpr := isTypeLiteral(aSym), isTypeLiteral(bSym)
+	if aIsExpr != bIsExpr {
+		return false, nil
+	}
+	if !aIsExpr {
+		// named types are compared by declarations
+		if !aSym.Equal(bSym) || len(aSym.TypeArgs) != len(bSym.TypeArgs) {
+			return false, nil
+		}
+		for i := range aSym.TypeArgs {
+			if same, err := identicalTypes(resolver, aSym.TypeArgs[i], bSym.TypeArgs[i]); !same || err != nil {
+				return same, err
+			}
+		}
+		return true, nil
+	}
+	switch x := aSym.Node.(type) {
+	case *ast.StarExpr:
+		y, ok := bSym.Node.(*ast.StarExpr)
+		if !ok {
+			return false, nil
+		}
+		return identicalTypes(resolver, aSym.TypeExpr(x.X), bSym.TypeExpr(y.X))
+	case *ast.Ellipsis:
+		y, ok := bSym.Node.(*ast.Ellipsis)
+		if !ok {
+			return false, nil
+		}
+		return identicalTypes(resolver, aSym.TypeExpr(x.Elt), bSym.TypeExpr(y.Elt))
+	case *ast.ArrayType:
+		y, ok := bSym.Node.(*ast.ArrayType)
+		if !ok || (x.Len == nil) != (y.Len == nil) {
+			return false, nil
+		}
+		if x.Len != nil && types.ExprString(x.Len) != types.ExprString(y.Len) {
+			return false, nil
+		}
+		return identicalTypes(resolver, aSym.TypeExpr(x.Elt), bSym.TypeExpr(y.Elt))
+	case *ast.MapType:
+		y, ok := bSym.Node.(*ast.MapType)
+		if !ok {
+			return false, nil
+		}
+		if same, err := identicalTypes(resolver, aSym.TypeExpr(x.Key), bSym.TypeExpr(y.Key)); !same || err != nil {
+			return same, err
+		}
+		return identicalTypes(resolver, aSym.TypeExpr(x.Value), bSym.TypeExpr(y.Value))
+	case *ast.ChanType:
+		y, ok := bSym.Node.(*ast.ChanType)
+		if !ok || x.Dir != y.Dir {
+			return false, nil
+		}
+		return identicalTypes(resolver, aSym.TypeExpr(x.Value), bSym.TypeExpr(y.Value))
+	case *ast.FuncType:
+		if _, ok := bSym.Node.(*ast.FuncType); !ok {
+			return false, nil
+		}
+		return identicalSignatures(resolver, aSym, bSym)
+	case *ast.StructType:
+		y, ok := bSym.Node.(*ast.StructType)
+		if !ok {
+			return false, nil
+		}
+		return identicalFields(resolver, aSym, flatFields(x.Fields), bSym, flatFields(y.Fields), true)
+	case *ast.InterfaceType:
+		y, ok := bSym.Node.(*ast.InterfaceType)
+		if !ok {
+			return false, nil
+		}
+		aMethods, bMethods := flatFields(x.Methods), flatFields(y.Methods)
+		sortByName(aMethods)
+		sortByName(bMethods)
+		return identicalFields(resolver, aSym, aMethods, bSym, bMethods, false)
+	}
+	return false, nil
+}
+
+// check that functions types (symbols of *ast.FuncType) have identical parameters and results (names are ignored)
+func identicalSignatures(resolver Resolver, a, b *Symbol) (bool, error) {
+	x, y := a.Node.(*ast.FuncType), b.Node.(*ast.FuncType)
+	if same, err := identicalTypeLists(resolver, a, fieldTypes(x.Params), b, fieldTypes(y.Params)); !same || err != nil {
+		return same, err
+	}
+	return identicalTypeLists(resolver, a, fieldTypes(x.Results), b, fieldTypes(y.Results))
+}
+
+func identicalTypeLists(resolver Resolver, aScope *Symbol, a []ast.Expr, bScope *Symbol, b []ast.Expr) (bool, error) {
+	if len(a) != len(b) {
+		return false, nil
+	}
+	for i := range a {
+		if same, err := identicalTypes(resolver, aScope.TypeExpr(a[i]), bScope.TypeExpr(b[i])); !same || err != nil {
+			return same, err
+		}
+	}
+	return true, nil
+}
+
+func identicalFields(resolver Resolver, aScope *Symbol, a []namedField, bScope *Symbol, b []namedField, tags bool) (bool, error) {
+	if len(a) != len(b) {
+		return false, nil
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		if x.name != y.name || (tags && x.tag != y.tag) {
+			return false, nil
+		}
+		if same, err := identicalTypes(resolver, aScope.TypeExpr(x.field.Type), bScope.TypeExpr(y.field.Type)); !same || err != nil {
+			return same, err
+		}
+	}
+	return true, nil
+}
+
+func sortByName(list []namedField) {
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].name < list[j].name
+	})
+}
+
+// symbol is a type literal expression (not a named, built-in type or type parameter)
+func isTypeLiteral(sym *Symbol) bool {
+	if sym.BuiltIn || sym.TypeParam {
+		return false
+	}
+	_, ok := sym.Node.(ast.Expr)
+	return ok
+}
+
+type namedField struct {
+	name  string // empty for embedded
+	tag   string
+	field *ast.Field
+}
+
+// fields with several names are expanded
+func flatFields(list *ast.FieldList) []namedField {
+	var ans []namedField
+	if list == nil {
+		return ans
+	}
+	for _, f := range list.List {
+		var tag string
+		if f.Tag != nil {
+			tag = f.Tag.Value
+		}
+		if len(f.Names) == 0 {
+			ans = append(ans, namedField{tag: tag, field: f})
+		}
+		for _, name := range f.Names {
+			ans = append(ans, namedField{name: name.Name, tag: tag, field: f})
+		}
+	}
+	return ans
+}
+
+// types of fields where every name is a separate item
+func fieldTypes(list *ast.FieldList) []ast.Expr {
+	var ans []ast.Expr
+	for _, f := range flatFields(list) {
+		ans = append(ans, f.field.Type)
+	}
+	return ans
+}
+
+func unparen(expr ast.Expr) ast.Expr {
+	for {
+		p, ok := expr.(*ast.ParenExpr)
+		if !ok {
+			return expr
+		}
+		expr = p.X
+	}
+}
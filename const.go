@@ -0,0 +1,348 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+)
+
+type Constant struct {
+	Value constant.Value
+	Type  *Symbol // declared (or inherited from typed operands) type, nil for untyped constants
+}
+
+// Evaluate value of constant declaration. Supported iota, implicit repetition of expressions in const groups,
+// arithmetic, comparison and logical operations, conversions, len of string constants and references to other
+// constants (including constants from other packages).
+func (sym *Symbol) ConstValue(resolver Resolver) (*Constant, error) {
+	return sym.constValue(resolver, 0)
+}
+
+func (sym *Symbol) constValue(resolver Resolver, depth int) (*Constant, error) {
+	if depth > maxResolveDepth {
+		return nil, errors.Errorf("too deep constant references for %v", sym.Name)
+	}
+	if !sym.IsConstant() {
+		return nil, errors.Errorf("%v is not a constant", sym.Name)
+	}
+	ident := sym.Node.(*ast.Ident)
+	spec, ok := sym.ParentNode.(*ast.ValueSpec)
+	if !ok {
+		if spec, ok = ident.Obj.Decl.(*ast.ValueSpec); !ok {
+			return nil, errors.Errorf("declaration of constant %v not found", sym.Name)
+		}
+	}
+	index := -1
+	for i, name := range spec.Names {
+		if name == ident || (index == -1 && name.Name == ident.Name) {
+			index = i
+		}
+	}
+	iota, typeExpr, values := constSpec(sym.File, spec)
+	if index < 0 || index >= len(values) {
+		return nil, errors.Errorf("missing value of constant %v", sym.Name)
+	}
+	ev := &constEvaluator{resolver: resolver, scope: sym, iota: iota, depth: depth}
+	c, err := ev.eval(values[index])
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluate constant %v", sym.Name)
+	}
+	if typeExpr != nil {
+		return ev.convert(c, sym.TypeExpr(typeExpr))
+	}
+	return c, nil
+}
+
+// iota, type and values of constant specification: values and type are inherited from previous
+// specification in group if omitted
+func constSpec(file *File, spec *ast.ValueSpec) (int, ast.Expr, []ast.Expr) {
+	var iota int
+	if obj := spec.Names[0].Obj; obj != nil {
+		if v, ok := obj.Data.(int); ok {
+			iota = v
+		}
+	}
+	if len(spec.Values) > 0 || file == nil {
+		return iota, spec.Type, spec.Values
+	}
+	for _, decl := range file.Ast.Decls {
+		group, ok := decl.(*ast.GenDecl)
+		if !ok || group.Tok != token.CONST {
+			continue
+		}
+		for i, item := range group.Specs {
+			if item != spec {
+				continue
+			}
+			iota = i
+			for j := i - 1; j >= 0; j-- {
+				prev := group.Specs[j].(*ast.ValueSpec)
+				if len(prev.Values) > 0 {
+					return iota, prev.Type, prev.Values
+				}
+			}
+			return iota, spec.Type, nil
+		}
+	}
+	return iota, spec.Type, nil
+}
+
+type constEvaluator struct {
+	resolver Resolver
+	scope    *Symbol // declaration of evaluated constant
+	iota     int
+	depth    int
+}
+
+func (ev *constEvaluator) eval(expr ast.Expr) (*Constant, error) {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		value := constant.MakeFromLiteral(v.Value, v.Kind, 0)
+		if value.Kind() == constant.Unknown {
+			return nil, errors.Errorf("malformed literal %v", v.Value)
+		}
+		return &Constant{Value: value}, nil
+	case *ast.ParenExpr:
+		return ev.eval(v.X)
+	case *ast.Ident:
+		switch v.Name {
+		case "iota":
+			return &Constant{Value: constant.MakeInt64(int64(ev.iota))}, nil
+		case "true", "false":
+			return &Constant{Value: constant.MakeBool(v.Name == "true")}, nil
+		}
+		return ev.reference(v.Name)
+	case *ast.SelectorExpr:
+		return ev.reference(realTypeQN(v))
+	case *ast.UnaryExpr:
+		x, err := ev.eval(v.X)
+		if err != nil {
+			return nil, err
+		}
+		var prec uint
+		if v.Op == token.XOR {
+			prec = ev.unsignedSize(x.Type)
+		}
+		return ev.typed(constant.UnaryOp(v.Op, x.Value, prec), x.Type)
+	case *ast.BinaryExpr:
+		return ev.binary(v)
+	case *ast.CallExpr:
+		return ev.call(v)
+	}
+	return nil, errors.Errorf("unsupported constant expression %v", ev.scope.TypeExpr(expr).Name)
+}
+
+func (ev *constEvaluator) reference(name string) (*Constant, error) {
+	sym, err := ev.resolver.FindSymbol(name, ev.scope.File)
+	if err != nil {
+		return nil, err
+	}
+	return sym.constValue(ev.resolver, ev.depth+1)
+}
+
+func (ev *constEvaluator) binary(expr *ast.BinaryExpr) (*Constant, error) {
+	x, err := ev.eval(expr.X)
+	if err != nil {
+		return nil, err
+	}
+	y, err := ev.eval(expr.Y)
+	if err != nil {
+		return nil, err
+	}
+	switch expr.Op {
+	case token.SHL, token.SHR:
+		shift, ok := constant.Uint64Val(constant.ToInt(y.Value))
+		if !ok {
+			return nil, errors.Errorf("invalid shift count %v", y.Value)
+		}
+		return ev.typed(constant.Shift(x.Value, expr.Op, uint(shift)), x.Type)
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return &Constant{Value: constant.MakeBool(constant.Compare(x.Value, expr.Op, y.Value))}, nil
+	}
+	tp := x.Type
+	if tp == nil {
+		tp = y.Type
+	}
+	op := expr.Op
+	if op == token.QUO && ev.isInteger(tp, x.Value, y.Value) {
+		// integer division
+		op = token.QUO_ASSIGN
+		x.Value, y.Value = constant.ToInt(x.Value), constant.ToInt(y.Value)
+	}
+	if (op == token.QUO || op == token.QUO_ASSIGN || op == token.REM) && constant.Sign(y.Value) == 0 {
+		return nil, errors.New("division by zero")
+	}
+	value := constant.BinaryOp(x.Value, op, y.Value)
+	if value.Kind() == constant.Unknown {
+		return nil, errors.Errorf("invalid operation %v %v %v", x.Value, expr.Op, y.Value)
+	}
+	return ev.typed(value, tp)
+}
+
+// conversions to types and built-in functions len, real, imag, complex
+func (ev *constEvaluator) call(expr *ast.CallExpr) (*Constant, error) {
+	if ident, ok := expr.Fun.(*ast.Ident); ok {
+		switch ident.Name {
+		case "len":
+			if len(expr.Args) != 1 {
+				break
+			}
+			arg, err := ev.eval(expr.Args[0])
+			if err != nil {
+				return nil, err
+			}
+			if arg.Value.Kind() != constant.String {
+				return nil, errors.New("len of non-string constant")
+			}
+			return &Constant{Value: constant.MakeInt64(int64(len(constant.StringVal(arg.Value))))}, nil
+		case "real", "imag":
+			if len(expr.Args) != 1 {
+				break
+			}
+			arg, err := ev.eval(expr.Args[0])
+			if err != nil {
+				return nil, err
+			}
+			if ident.Name == "real" {
+				return &Constant{Value: constant.Real(arg.Value)}, nil
+			}
+			return &Constant{Value: constant.Imag(arg.Value)}, nil
+		case "complex":
+			if len(expr.Args) != 2 {
+				break
+			}
+			re, err := ev.eval(expr.Args[0])
+			if err != nil {
+				return nil, err
+			}
+			im, err := ev.eval(expr.Args[1])
+			if err != nil {
+				return nil, err
+			}
+			value := constant.BinaryOp(constant.ToFloat(re.Value), token.ADD, constant.MakeImag(constant.ToFloat(im.Value)))
+			return &Constant{Value: value}, nil
+		}
+	}
+	if len(expr.Args) != 1 {
+		return nil, errors.Errorf("unsupported call in constant expression %v", ev.scope.TypeExpr(expr.Fun).Name)
+	}
+	arg, err := ev.eval(expr.Args[0])
+	if err != nil {
+		return nil, err
+	}
+	return ev.convert(arg, ev.scope.TypeExpr(expr.Fun))
+}
+
+// convert value to type (type expression)
+func (ev *constEvaluator) convert(c *Constant, typeExpr *Symbol) (*Constant, error) {
+	tp, err := typeExpr.Resolve(ev.resolver)
+	if err != nil {
+		return nil, err
+	}
+	if !(tp.BuiltIn && tp.Universe == UniverseType) && !tp.IsType() {
+		return nil, errors.Errorf("%v is not a type", typeExpr.Name)
+	}
+	value := c.Value
+	switch info := ev.basicInfo(tp); {
+	case info&types.IsInteger != 0:
+		if value.Kind() == constant.String {
+			break
+		}
+		value = constant.ToInt(value)
+		if value.Kind() != constant.Int {
+			return nil, errors.Errorf("constant %v truncated to %v", c.Value, tp.Name)
+		}
+	case info&types.IsFloat != 0:
+		value = constant.ToFloat(value)
+	case info&types.IsComplex != 0:
+		value = constant.ToComplex(value)
+	case info&types.IsString != 0:
+		if value.Kind() == constant.Int {
+			// conversion of code point
+			if code, ok := constant.Int64Val(value); ok {
+				value = constant.MakeString(string(rune(code)))
+			}
+		}
+	}
+	return ev.typed(value, tp)
+}
+
+// constant of type with check that value fits into basic underlying type
+func (ev *constEvaluator) typed(value constant.Value, tp *Symbol) (*Constant, error) {
+	basic := ev.basicKind(tp)
+	info := types.Typ[basic].Info()
+	var overflows bool
+	switch {
+	case info&types.IsInteger != 0 && value.Kind() == constant.Int:
+		bits := bitSize(basic)
+		min, max := constant.MakeInt64(0), constant.Shift(constant.MakeInt64(1), token.SHL, bits)
+		if info&types.IsUnsigned == 0 {
+			max = constant.Shift(constant.MakeInt64(1), token.SHL, bits-1)
+			min = constant.UnaryOp(token.SUB, max, 0)
+		}
+		overflows = constant.Compare(value, token.LSS, min) || constant.Compare(value, token.GEQ, max)
+	case basic == types.Float32:
+		f, _ := constant.Float32Val(constant.ToFloat(value))
+		overflows = math.IsInf(float64(f), 0)
+	case basic == types.Float64:
+		f, _ := constant.Float64Val(constant.ToFloat(value))
+		overflows = math.IsInf(f, 0)
+	}
+	if overflows {
+		return nil, errors.Errorf("constant %v overflows %v", value, tp.Name)
+	}
+	return &Constant{Value: value, Type: tp}, nil
+}
+
+// properties of basic underlying type or 0
+func (ev *constEvaluator) basicInfo(tp *Symbol) types.BasicInfo {
+	return types.Typ[ev.basicKind(tp)].Info()
+}
+
+// kind of basic underlying type or types.Invalid
+func (ev *constEvaluator) basicKind(tp *Symbol) types.BasicKind {
+	if tp == nil {
+		return types.Invalid
+	}
+	underlying, err := tp.Underlying(ev.resolver)
+	if err != nil || !underlying.BuiltIn {
+		return types.Invalid
+	}
+	return underlying.Basic
+}
+
+func (ev *constEvaluator) isInteger(tp *Symbol, values ...constant.Value) bool {
+	if tp != nil {
+		return ev.basicInfo(tp)&types.IsInteger != 0
+	}
+	for _, v := range values {
+		if v.Kind() != constant.Int {
+			return false
+		}
+	}
+	return true
+}
+
+// size in bits of unsigned type (for bitwise complement) or 0
+func (ev *constEvaluator) unsignedSize(tp *Symbol) uint {
+	if tp == nil || ev.basicInfo(tp)&types.IsUnsigned == 0 {
+		return 0
+	}
+	return bitSize(ev.basicKind(tp))
+}
+
+// size in bits of integer or float basic type (int, uint and uintptr are considered 64 bits)
+func bitSize(basic types.BasicKind) uint {
+	switch basic {
+	case types.Int8, types.Uint8:
+		return 8
+	case types.Int16, types.Uint16:
+		return 16
+	case types.Int32, types.Uint32, types.Float32:
+		return 32
+	}
+	return 64
+}
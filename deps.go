@@ -0,0 +1,225 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Transitive closure of types referenced by declaration
+type Dependencies struct {
+	Symbol     *Symbol
+	Imports    []*ImportDependencies // referenced declarations grouped by import path and sorted by path
+	Cycles     [][]*Symbol           // chains of declarations where the last one references the first one
+	Unresolved []string              // qualified names that can't be resolved (package is not scanned)
+}
+
+type ImportDependencies struct {
+	Import  string
+	Std     bool      // import from standard library
+	Symbols []*Symbol // declarations sorted by name
+}
+
+// Find all declarations that are transitively referenced by type or function: field and embedded types,
+// signatures of methods, underlying types and constraints of type parameters. Types from the standard
+// library are included, but their own dependencies are not followed if stopAtStd is set.
+func (prj *Project) Dependencies(sym *Symbol, stopAtStd bool) (*Dependencies, error) {
+	if !sym.IsType() && !sym.IsFunction() {
+		return nil, errors.Errorf("%v is not a type or function", sym.Name)
+	}
+	dw := &depWalker{
+		project:    prj,
+		stopAtStd:  stopAtStd,
+		visited:    make(map[string]bool),
+		onStack:    make(map[string]int),
+		found:      make(map[string]*Symbol),
+		unresolved: make(map[string]bool),
+	}
+	if err := dw.visit(sym); err != nil {
+		return nil, err
+	}
+	deps := &Dependencies{Symbol: sym, Cycles: dw.cycles}
+	groups := make(map[string]*ImportDependencies)
+	for id, found := range dw.found {
+		if id == sym.ID() {
+			continue
+		}
+		group, ok := groups[found.Import.Import]
+		if !ok {
+			group = &ImportDependencies{Import: found.Import.Import, Std: found.Import.IsStd()}
+			groups[found.Import.Import] = group
+			deps.Imports = append(deps.Imports, group)
+		}
+		group.Symbols = append(group.Symbols, found)
+	}
+	sort.Slice(deps.Imports, func(i, j int) bool {
+		return deps.Imports[i].Import < deps.Imports[j].Import
+	})
+	for _, group := range deps.Imports {
+		sort.Slice(group.Symbols, func(i, j int) bool {
+			return group.Symbols[i].Name < group.Symbols[j].Name
+		})
+	}
+	for name := range dw.unresolved {
+		deps.Unresolved = append(deps.Unresolved, name)
+	}
+	sort.Strings(deps.Unresolved)
+	return deps, nil
+}
+
+// Import is a package of standard library
+func (imp *Import) IsStd() bool {
+	if imp.Directory != "" {
+		goRoot := filepath.Join(runtime.GOROOT(), "src") + string(filepath.Separator)
+		return strings.HasPrefix(imp.Directory, goRoot)
+	}
+	// not scanned import: standard packages have no domain in path
+	first := strings.Split(imp.Import, "/")[0]
+	return !strings.Contains(first, ".")
+}
+
+type depWalker struct {
+	project    *Project
+	stopAtStd  bool
+	visited    map[string]bool
+	onStack    map[string]int // position of declaration in stack
+	stack      []*Symbol
+	found      map[string]*Symbol
+	cycles     [][]*Symbol
+	unresolved map[string]bool
+}
+
+func (dw *depWalker) visit(sym *Symbol) error {
+	id := sym.ID()
+	if pos, ok := dw.onStack[id]; ok {
+		dw.cycles = append(dw.cycles, append([]*Symbol{}, dw.stack[pos:]...))
+		return nil
+	}
+	if dw.visited[id] {
+		return nil
+	}
+	dw.visited[id] = true
+	decl := *sym
+	decl.TypeArgs = nil
+	dw.found[id] = &decl
+	if dw.stopAtStd && sym.Import != nil && sym.Import.IsStd() {
+		return nil
+	}
+	dw.onStack[id] = len(dw.stack)
+	dw.stack = append(dw.stack, &decl)
+	defer func() {
+		dw.stack = dw.stack[:len(dw.stack)-1]
+		delete(dw.onStack, id)
+	}()
+
+	switch v := sym.Node.(type) {
+	case *ast.TypeSpec:
+		if err := dw.fields(&decl, v.TypeParams); err != nil {
+			return err
+		}
+		if err := dw.expr(&decl, v.Type); err != nil {
+			return err
+		}
+		for _, method := range sym.Import.MethodsOf(sym.Name) {
+			if err := dw.expr(method, method.Node.(*ast.FuncDecl).Type); err != nil {
+				return errors.Wrapf(err, "method %v", method.Name)
+			}
+		}
+	case *ast.FuncDecl:
+		return dw.expr(&decl, v.Type)
+	}
+	return nil
+}
+
+// visit declarations referenced by type expression
+func (dw *depWalker) expr(scope *Symbol, expr ast.Expr) error {
+	switch v := expr.(type) {
+	case nil:
+		return nil
+	case *ast.Ident, *ast.SelectorExpr:
+		return dw.reference(scope, v)
+	case *ast.IndexExpr:
+		if err := dw.reference(scope, v.X); err != nil {
+			return err
+		}
+		return dw.expr(scope, v.Index)
+	case *ast.IndexListExpr:
+		if err := dw.reference(scope, v.X); err != nil {
+			return err
+		}
+		for _, index := range v.Indices {
+			if err := dw.expr(scope, index); err != nil {
+				return err
+			}
+		}
+	case *ast.ParenExpr:
+		return dw.expr(scope, v.X)
+	case *ast.StarExpr:
+		return dw.expr(scope, v.X)
+	case *ast.UnaryExpr:
+		return dw.expr(scope, v.X)
+	case *ast.BinaryExpr:
+		if err := dw.expr(scope, v.X); err != nil {
+			return err
+		}
+		return dw.expr(scope, v.Y)
+	case *ast.Ellipsis:
+		return dw.expr(scope, v.Elt)
+	case *ast.ArrayType:
+		return dw.expr(scope, v.Elt)
+	case *ast.ChanType:
+		return dw.expr(scope, v.Value)
+	case *ast.MapType:
+		if err := dw.expr(scope, v.Key); err != nil {
+			return err
+		}
+		return dw.expr(scope, v.Value)
+	case *ast.FuncType:
+		if err := dw.fields(scope, v.TypeParams); err != nil {
+			return err
+		}
+		if err := dw.fields(scope, v.Params); err != nil {
+			return err
+		}
+		return dw.fields(scope, v.Results)
+	case *ast.StructType:
+		return dw.fields(scope, v.Fields)
+	case *ast.InterfaceType:
+		return dw.fields(scope, v.Methods)
+	}
+	return nil
+}
+
+func (dw *depWalker) fields(scope *Symbol, list *ast.FieldList) error {
+	if list == nil {
+		return nil
+	}
+	for _, f := range list.List {
+		if err := dw.expr(scope, f.Type); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// visit declaration of type name
+func (dw *depWalker) reference(scope *Symbol, expr ast.Expr) error {
+	target, err := scope.TypeExpr(expr).Resolve(dw.project)
+	if err != nil {
+		name := realTypeQN(expr)
+		if _, isQualified := expr.(*ast.SelectorExpr); isQualified {
+			if imp, findErr := dw.project.FindPackageImport(strings.Split(name, ".")[0], scope.File); findErr == nil {
+				dw.unresolved[imp.Import+"."+strings.SplitN(name, ".", 2)[1]] = true
+				return nil
+			}
+		}
+		return errors.Wrapf(err, "resolve %v", name)
+	}
+	if target.BuiltIn || target.TypeParam || !target.IsType() {
+		return nil
+	}
+	return dw.visit(target)
+}
@@ -0,0 +1,287 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Documentation of declaration: doc comment and line comment. For single declarations
+// (type A struct{}) doc comment of the declaration group is used. Directives are not included.
+func (sym *Symbol) Doc() string {
+	return commentText(sym.comments()...)
+}
+
+// Directives (//tool:name key=value) in doc and line comments of declaration
+func (sym *Symbol) Directives() Directives {
+	return ParseDirectives(sym.comments()...)
+}
+
+func (sym *Symbol) comments() []*ast.CommentGroup {
+	switch v := sym.node().(type) {
+	case *ast.FuncDecl:
+		return []*ast.CommentGroup{v.Doc}
+	case *ast.TypeSpec:
+		return []*ast.CommentGroup{groupDoc(sym, v, v.Doc), v.Comment}
+	case *ast.Ident:
+		if spec, ok := sym.ParentNode.(*ast.ValueSpec); ok {
+			return []*ast.CommentGroup{groupDoc(sym, spec, spec.Doc), spec.Comment}
+		}
+		if v.Obj != nil {
+			if spec, ok := v.Obj.Decl.(*ast.ValueSpec); ok {
+				return []*ast.CommentGroup{groupDoc(sym, spec, spec.Doc), spec.Comment}
+			}
+		}
+	}
+	return nil
+}
+
+// doc of specification or doc of declaration group without parentheses
+func groupDoc(sym *Symbol, spec ast.Spec, doc *ast.CommentGroup) *ast.CommentGroup {
+	if doc != nil {
+		return doc
+	}
+	if decl, ok := sym.ParentNode.(*ast.GenDecl); ok {
+		if !decl.Lparen.IsValid() {
+			return decl.Doc
+		}
+		return nil
+	}
+	if sym.File == nil {
+		return nil
+	}
+	for _, decl := range sym.File.Ast.Decls {
+		if group, ok := decl.(*ast.GenDecl); ok && !group.Lparen.IsValid() && len(group.Specs) == 1 && group.Specs[0] == spec {
+			return group.Doc
+		}
+	}
+	return nil
+}
+
+// Documentation of method: comments of interface method or doc comment of concrete method
+func (m *Method) Doc() string {
+	return commentText(m.comments()...)
+}
+
+// Directives in comments of method
+func (m *Method) Directives() Directives {
+	return ParseDirectives(m.comments()...)
+}
+
+func (m *Method) comments() []*ast.CommentGroup {
+	if m.Function != nil {
+		return []*ast.CommentGroup{m.Function.Raw.Doc}
+	}
+	if m.Raw != nil {
+		return []*ast.CommentGroup{m.Raw.Doc, m.Raw.Comment}
+	}
+	return nil
+}
+
+// Doc comment of function
+func (fn *Function) Doc() string {
+	return commentText(fn.Raw.Doc)
+}
+
+// Directives in doc comment of function
+func (fn *Function) Directives() Directives {
+	return ParseDirectives(fn.Raw.Doc)
+}
+
+// Directives in comments of field
+func (f *Field) Directives() Directives {
+	return ParseDirectives(f.Raw.Doc, f.Raw.Comment)
+}
+
+func commentText(groups ...*ast.CommentGroup) string {
+	var lines []string
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		if txt := strings.TrimSpace(group.Text()); txt != "" {
+			lines = append(lines, txt)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Machine-readable comment: //symbols:mutate target=UserDTO exclude=Password,Token value
+type Directive struct {
+	Name   string   // tool and name (symbols:mutate)
+	Args   []string // positional arguments (value)
+	Params map[string]string
+}
+
+// Value of parameter
+func (d *Directive) Get(key string) (string, bool) {
+	v, ok := d.Params[key]
+	return v, ok
+}
+
+// Value of parameter or default value
+func (d *Directive) String(key string, defaultValue string) string {
+	if v, ok := d.Params[key]; ok {
+		return v
+	}
+	return defaultValue
+}
+
+// Comma separated values of parameter
+func (d *Directive) List(key string) []string {
+	v, ok := d.Params[key]
+	if !ok || v == "" {
+		return nil
+	}
+	var ans []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ans = append(ans, item)
+		}
+	}
+	return ans
+}
+
+// Integer value of parameter or default value if parameter not defined
+func (d *Directive) Int(key string, defaultValue int) (int, error) {
+	v, ok := d.Params[key]
+	if !ok {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parameter %v of %v", key, d.Name)
+	}
+	return n, nil
+}
+
+// Boolean value of parameter: positional argument with the same name or parameter with boolean value
+func (d *Directive) Bool(key string) (bool, error) {
+	if d.Has(key) {
+		if v, ok := d.Params[key]; ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return false, errors.Wrapf(err, "parameter %v of %v", key, d.Name)
+			}
+			return b, nil
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// Is parameter or positional argument defined
+func (d *Directive) Has(key string) bool {
+	if _, ok := d.Params[key]; ok {
+		return true
+	}
+	for _, arg := range d.Args {
+		if arg == key {
+			return true
+		}
+	}
+	return false
+}
+
+type Directives []*Directive
+
+// First directive by name (tool:name)
+func (ds Directives) Get(name string) *Directive {
+	for _, d := range ds {
+		if d.Name == name {
+			return d
+		}
+	}
+	return nil
+}
+
+// Is directive defined
+func (ds Directives) Has(name string) bool {
+	return ds.Get(name) != nil
+}
+
+// Parse directives (comments without space after // in form tool:name) from comments. Malformed
+// directives are skipped.
+func ParseDirectives(groups ...*ast.CommentGroup) Directives {
+	var ans Directives
+	for _, group := range groups {
+		if group == nil {
+			continue
+		}
+		for _, comment := range group.List {
+			if d, err := ParseDirective(comment.Text); err == nil {
+				ans = append(ans, d)
+			}
+		}
+	}
+	return ans
+}
+
+// Parse single directive line: //tool:name arg key=value key="quoted value"
+func ParseDirective(line string) (*Directive, error) {
+	if !strings.HasPrefix(line, "//") || strings.HasPrefix(line, "// ") {
+		return nil, errors.New("directive should be line comment without space after //")
+	}
+	tokens, err := splitDirective(line[2:])
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 || !isDirectiveName(tokens[0]) {
+		return nil, errors.Errorf("%v is not a directive", line)
+	}
+	d := &Directive{Name: tokens[0], Params: make(map[string]string)}
+	for _, token := range tokens[1:] {
+		if idx := strings.Index(token, "="); idx > 0 {
+			d.Params[token[:idx]] = token[idx+1:]
+		} else {
+			d.Args = append(d.Args, token)
+		}
+	}
+	return d, nil
+}
+
+// tool:name where tool and name are lower-case letters or digits
+func isDirectiveName(s string) bool {
+	idx := strings.Index(s, ":")
+	if idx <= 0 || idx == len(s)-1 {
+		return false
+	}
+	for i, r := range s {
+		if i == idx {
+			continue
+		}
+		if !(unicode.IsLower(r) || unicode.IsDigit(r) || (i > idx && (r == '-' || r == '_'))) {
+			return false
+		}
+	}
+	return true
+}
+
+// split by spaces, values could be quoted: key="a b"
+func splitDirective(s string) ([]string, error) {
+	var ans []string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return ans, nil
+		}
+		var token strings.Builder
+		for s != "" && s[0] != ' ' && s[0] != '\t' {
+			if s[0] != '"' && s[0] != '`' {
+				token.WriteByte(s[0])
+				s = s[1:]
+				continue
+			}
+			quoted, err := strconv.QuotedPrefix(s)
+			if err != nil {
+				return nil, errors.Wrap(err, "parse quoted value")
+			}
+			value, _ := strconv.Unquote(quoted)
+			token.WriteString(value)
+			s = s[len(quoted):]
+		}
+		ans = append(ans, token.String())
+	}
+}
@@ -0,0 +1,103 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/constant"
+	"go/token"
+)
+
+type Enum struct {
+	Type   *Symbol
+	Values []*EnumValue
+}
+
+type EnumValue struct {
+	Name   string
+	Value  constant.Value
+	Symbol *Symbol
+	Doc    string
+}
+
+// Named types of the project package with constants of the type declared in the package. Result in order
+// of constants declarations.
+func (prj *Project) Enums() []*Enum {
+	var ans []*Enum
+	var byType = make(map[string]*Enum)
+	for _, item := range evalConstants(prj, prj.Package) {
+		tp := item.Type
+		if tp == nil || tp.BuiltIn || tp.Import == nil || tp.Import.Import != prj.Package.Import {
+			continue
+		}
+		enum, ok := byType[tp.Name]
+		if !ok {
+			enum = &Enum{Type: tp}
+			byType[tp.Name] = enum
+			ans = append(ans, enum)
+		}
+		enum.Values = append(enum.Values, item.EnumValue)
+	}
+	return ans
+}
+
+// Constants of the named type declared in the same package as the type in declaration order.
+// Constants which values could not be evaluated are skipped.
+func (sym *Symbol) EnumValues(resolver Resolver) ([]*EnumValue, error) {
+	if !sym.IsType() {
+		return nil, errors.Errorf("%v is not a named type", sym.Name)
+	}
+	sym, err := sym.Unalias(resolver)
+	if err != nil {
+		return nil, err
+	}
+	var ans []*EnumValue
+	for _, item := range evalConstants(resolver, sym.Import) {
+		if item.Type != nil && !item.Type.BuiltIn && item.Type.Equal(sym) {
+			ans = append(ans, item.EnumValue)
+		}
+	}
+	return ans, nil
+}
+
+type typedValue struct {
+	*EnumValue
+	Type *Symbol
+}
+
+// evaluated constants of package in declaration order
+func evalConstants(resolver Resolver, imp *Import) []typedValue {
+	var ans []typedValue
+	for _, file := range imp.Files {
+		for _, decl := range file.Ast.Decls {
+			group, ok := decl.(*ast.GenDecl)
+			if !ok || group.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range group.Specs {
+				spec := spec.(*ast.ValueSpec)
+				for _, name := range spec.Names {
+					if name.Name == "_" {
+						continue
+					}
+					sym := &Symbol{Import: imp, File: file, Node: name, ParentNode: spec, Name: name.Name}
+					c, err := sym.ConstValue(resolver)
+					if err != nil {
+						continue
+					}
+					// constants declared with alias belong to the aliased type
+					tp := c.Type
+					if tp != nil {
+						if tp, err = tp.Unalias(resolver); err != nil {
+							continue
+						}
+					}
+					ans = append(ans, typedValue{
+						EnumValue: &EnumValue{Name: name.Name, Value: c.Value, Symbol: sym, Doc: sym.Doc()},
+						Type:      tp,
+					})
+				}
+			}
+		}
+	}
+	return ans
+}
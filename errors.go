@@ -0,0 +1,54 @@
+package symbols
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+var (
+	ErrNotStruct      = errors.New("is not a struct")
+	ErrNotInterface   = errors.New("is not a interface")
+	ErrImportNotFound = errors.New("import scanned but not found")
+)
+
+// Type expression that can not be processed by analysis or generator
+type UnsupportedTypeError struct {
+	Expr ast.Expr
+	Pos  token.Position
+}
+
+func (e *UnsupportedTypeError) Error() string {
+	if e.Pos.IsValid() {
+		return fmt.Sprintf("%v: unsupported type expression %v", e.Pos, types.ExprString(e.Expr))
+	}
+	return fmt.Sprintf("unsupported type expression %v", types.ExprString(e.Expr))
+}
+
+// Import path that is not a valid quoted string
+type ImportPathError struct {
+	Path string // raw (quoted) value
+	Pos  token.Position
+	Err  error
+}
+
+func (e *ImportPathError) Error() string {
+	return fmt.Sprintf("%v: invalid import path %v: %v", e.Pos, e.Path, e.Err)
+}
+
+func (e *ImportPathError) Unwrap() error { return e.Err }
+
+// Types that can't be checked by analysis, other results are still valid
+type UncheckedTypesError struct {
+	Types  []*Symbol
+	Errors []error // reason for each type
+}
+
+func (e *UncheckedTypesError) Error() string {
+	if len(e.Types) == 1 {
+		return fmt.Sprintf("type %v can't be checked: %v", e.Types[0].Name, e.Errors[0])
+	}
+	return fmt.Sprintf("%v types can't be checked, first %v: %v", len(e.Types), e.Types[0].Name, e.Errors[0])
+}
@@ -0,0 +1,209 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+)
+
+// Type parameters of generic type or function as symbols. For methods of generic types
+// these are parameters declared in receiver (func (p *Page[T]) ...).
+func (sym *Symbol) TypeParams() []*Symbol {
+	var ans []*Symbol
+	for _, ident := range typeParamIdents(sym.Node) {
+		ans = append(ans, &Symbol{
+			Import:     sym.Import,
+			File:       sym.File,
+			Node:       ident,
+			ParentNode: sym.Node,
+			Name:       ident.Name,
+			TypeParam:  true,
+			scope:      sym,
+		})
+	}
+	return ans
+}
+
+// Is type or function declared with type parameters
+func (sym *Symbol) IsGeneric() bool {
+	return len(typeParamIdents(sym.Node)) > 0
+}
+
+// Constraint of type parameter as type expression. Returns nil for non type parameters and for
+// type parameters declared in method receivers.
+func (sym *Symbol) Constraint() *Symbol {
+	if !sym.TypeParam || sym.scope == nil {
+		return nil
+	}
+	var list *ast.FieldList
+	switch v := sym.ParentNode.(type) {
+	case *ast.TypeSpec:
+		list = v.TypeParams
+	case *ast.FuncDecl:
+		list = v.Type.TypeParams
+	}
+	if list == nil {
+		return nil
+	}
+	for _, f := range list.List {
+		for _, name := range f.Names {
+			if name == sym.Node {
+				return sym.scope.TypeExpr(f.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// Instantiate generic type or function by type arguments in order of type parameters.
+// Type parameters in fields, methods and underlying type of instantiated symbol are resolved to arguments.
+func (sym *Symbol) Instantiate(args ...*Symbol) (*Symbol, error) {
+	params := typeParamIdents(sym.Node)
+	if len(params) == 0 {
+		return nil, errors.Errorf("%v is not generic", sym.Name)
+	}
+	if len(params) != len(args) {
+		return nil, errors.Errorf("%v expects %v type arguments, got %v", sym.Name, len(params), len(args))
+	}
+	inst := *sym
+	inst.TypeArgs = args
+	return &inst, nil
+}
+
+// Type expression declared in scope of the symbol as symbol: type parameters of generic symbol are visible
+// in the expression
+func (sym *Symbol) TypeExpr(expr ast.Expr) *Symbol {
+	ref := typeExprSymbol(expr, sym.File, sym.Import)
+	if sym.IsGeneric() {
+		ref.scope = sym
+	} else {
+		ref.scope = sym.scope
+	}
+	return ref
+}
+
+// find type parameter (or type argument for instantiated symbols) in scope of type expression
+func (sym *Symbol) lookupTypeParam(name string) *Symbol {
+	if name == "_" {
+		return nil
+	}
+	for scope := sym.scope; scope != nil; scope = scope.scope {
+		for i, ident := range typeParamIdents(scope.Node) {
+			if ident.Name != name {
+				continue
+			}
+			if i < len(scope.TypeArgs) {
+				return scope.TypeArgs[i]
+			}
+			return scope.TypeParams()[i]
+		}
+	}
+	return nil
+}
+
+// Resolve type name (optionally qualified or instantiated) of type expression symbol to declaration,
+// type parameter or type argument. Other symbols are returned as is.
+func (sym *Symbol) Resolve(resolver Resolver) (*Symbol, error) {
+	expr, ok := sym.Node.(ast.Expr)
+	if !ok || sym.TypeParam {
+		return sym, nil
+	}
+	switch v := unparen(expr).(type) {
+	case *ast.Ident:
+		if param := sym.lookupTypeParam(v.Name); param != nil {
+			return param, nil
+		}
+		return resolver.FindSymbol(v.Name, sym.File)
+	case *ast.SelectorExpr:
+		return resolver.FindSymbol(realTypeQN(v), sym.File)
+	case *ast.IndexExpr:
+		return sym.instantiate(resolver, v.X, []ast.Expr{v.Index})
+	case *ast.IndexListExpr:
+		return sym.instantiate(resolver, v.X, v.Indices)
+	}
+	return sym, nil
+}
+
+func (sym *Symbol) instantiate(resolver Resolver, base ast.Expr, indices []ast.Expr) (*Symbol, error) {
+	generic, err := sym.TypeExpr(base).Resolve(resolver)
+	if err != nil {
+		return nil, err
+	}
+	var args []*Symbol
+	for _, index := range indices {
+		args = append(args, sym.TypeExpr(index))
+	}
+	return generic.Instantiate(args...)
+}
+
+func typeParamIdents(node ast.Node) []*ast.Ident {
+	switch v := node.(type) {
+	case *ast.TypeSpec:
+		return fieldsIdents(v.TypeParams)
+	case *ast.FuncDecl:
+		if v.Recv != nil && len(v.Recv.List) > 0 {
+			return receiverTypeParams(v.Recv.List[0].Type)
+		}
+		return fieldsIdents(v.Type.TypeParams)
+	}
+	return nil
+}
+
+func receiverTypeParams(expr ast.Expr) []*ast.Ident {
+	var indices []ast.Expr
+	switch v := unparen(expr).(type) {
+	case *ast.StarExpr:
+		return receiverTypeParams(v.X)
+	case *ast.IndexExpr:
+		indices = []ast.Expr{v.Index}
+	case *ast.IndexListExpr:
+		indices = v.Indices
+	}
+	var ans []*ast.Ident
+	for _, index := range indices {
+		if ident, ok := index.(*ast.Ident); ok {
+			ans = append(ans, ident)
+		}
+	}
+	return ans
+}
+
+func fieldsIdents(list *ast.FieldList) []*ast.Ident {
+	if list == nil {
+		return nil
+	}
+	var ans []*ast.Ident
+	for _, f := range list.List {
+		ans = append(ans, f.Names...)
+	}
+	return ans
+}
+
+// base type of type expression: pointers, arrays and slices are skipped, type names are resolved
+func (sym *Symbol) baseType(resolver Resolver) (*Symbol, error) {
+	current := sym
+	for depth := 0; ; depth++ {
+		if depth > maxResolveDepth {
+			return nil, errors.Errorf("too deep type expression %v", sym.Name)
+		}
+		if !isTypeLiteral(current) {
+			return current, nil
+		}
+		switch v := current.Node.(type) {
+		case *ast.ParenExpr:
+			current = current.TypeExpr(v.X)
+		case *ast.StarExpr:
+			current = current.TypeExpr(v.X)
+		case *ast.ArrayType:
+			current = current.TypeExpr(v.Elt)
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+			target, err := current.Resolve(resolver)
+			if err != nil {
+				return nil, err
+			}
+			current = target
+		default:
+			// map, struct, func, chan or interface literal
+			return current, nil
+		}
+	}
+}
@@ -1,14 +1,17 @@
 module github.com/reddec/symbols
 
-go 1.13
+go 1.21
 
 require (
 	github.com/dave/jennifer v1.2.0
-	github.com/davecgh/go-spew v1.1.1
 	github.com/iancoleman/strcase v0.0.0-20180726023541-3605ed457bf7
 	github.com/jessevdk/go-flags v1.4.0
-	github.com/pkg/errors v0.8.0
-	github.com/pmezard/go-difflib v1.0.0
+	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.2.2
 	golang.org/x/tools v0.0.0-20181207222222-4c874b978acb
 )
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+)
@@ -0,0 +1,302 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"strings"
+)
+
+// Canonical identifier of symbol: import/path.Name for package level declarations, import/path.Type.Method
+// for methods, import/path for packages and name for built-in symbols, type parameters and type expressions
+func (sym *Symbol) ID() string {
+	if sym == nil {
+		return ""
+	}
+	if NodeKind(sym.Node) == KindPackage && sym.Import != nil {
+		return sym.Import.Import
+	}
+	if sym.BuiltIn || sym.TypeParam || sym.Import == nil || isTypeLiteral(sym) {
+		return sym.Name
+	}
+	if sym.IsMethod() {
+		if fn, err := sym.Function(); err == nil {
+			return sym.Import.Import + "." + fn.Receiver + "." + sym.Name
+		}
+	}
+	return sym.Import.Import + "." + sym.Name
+}
+
+// Canonical identifier of method: import/path.Type.Method where Type is receiver type or interface
+func (m *Method) ID() string {
+	if m.Function != nil && m.scope != nil && m.scope.Import != nil {
+		return m.scope.Import.Import + "." + m.Function.Receiver + "." + m.Name
+	}
+	if m.Interface != nil {
+		return m.Interface.ID() + "." + m.Name
+	}
+	return m.Name
+}
+
+// Canonical identifier of field: import/path.Type.Field
+func (f *Field) ID() string {
+	return f.Owner.ID() + "." + f.Name
+}
+
+// Declaration, method or field found by canonical identifier
+type Selection struct {
+	Symbol *Symbol  // declaration or named type the method or field is selected from (see Field.Owner for declaring struct)
+	Method *Method  // selected method, nil if not a method
+	Field  *Field   // selected field, nil if not a field
+	Type   *Symbol  // resolved type of field, function type of method or the declaration itself
+	Path   []string // names of embedded fields through which the member is promoted
+}
+
+func (sel *Selection) ID() string {
+	switch {
+	case sel.Method != nil:
+		return sel.Symbol.ID() + "." + sel.Method.Name
+	case sel.Field != nil:
+		return sel.Field.ID()
+	}
+	return sel.Symbol.ID()
+}
+
+// Find symbol, method or field by canonical identifier (see Symbol.ID) in scanned imports.
+// Built-in types are also accepted.
+func (prj *Project) Lookup(id string) (*Selection, error) {
+	if imp := prj.Imports.ByImport(id); imp != nil && len(imp.Files) > 0 {
+		pkg := &Symbol{Import: imp, File: imp.Files[0], Node: imp.Files[0].Ast, Name: imp.Package}
+		return &Selection{Symbol: pkg, Type: pkg}, nil
+	}
+	imp, names := prj.splitID(id)
+	if imp == nil {
+		// identifiers of declarations are qualified by import path
+		if sym := universeSymbol(id, nil); sym != nil {
+			return &Selection{Symbol: sym}, nil
+		}
+		return nil, errors.Errorf("import of %v not found", id)
+	}
+	if len(names) == 0 {
+		return nil, errors.Errorf("invalid identifier %v", id)
+	}
+	sym := imp.FindSymbol(names[0])
+	if sym == nil {
+		return nil, errors.Errorf("symbol %v not found in %v", names[0], imp.Import)
+	}
+	sel, err := prj.selectPath(sym, names[1:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "select %v", id)
+	}
+	return sel, nil
+}
+
+// split identifier to the longest scanned import path and names after it
+func (prj *Project) splitID(id string) (*Import, []string) {
+	var dir, rest = "", id
+	if idx := strings.LastIndex(id, "/"); idx != -1 {
+		dir, rest = id[:idx+1], id[idx+1:]
+	}
+	parts := strings.Split(rest, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		if imp := prj.Imports.ByImport(dir + strings.Join(parts[:i], ".")); imp != nil {
+			return imp, parts[i:]
+		}
+	}
+	return nil, nil
+}
+
+// Find declaration, package or member by path relative to file: Name, pkg, pkg.Name, Type.Method, Type.Field.Field or
+// pkg.Type.Field.Method. Fields are followed through pointers, embedded fields and other packages.
+func (prj *Project) FindMember(path string, sourceFile *File) (*Selection, error) {
+	names := strings.Split(path, ".")
+	sym, err := prj.FindSymbol(names[0], sourceFile)
+	if err != nil && len(names) == 1 {
+		pkg, pkgErr := prj.FindPackageSymbol(names[0], sourceFile)
+		if pkgErr != nil {
+			return nil, err
+		}
+		return &Selection{Symbol: pkg, Type: pkg}, nil
+	}
+	if err != nil && len(names) > 1 {
+		// qualified by package name or alias
+		imp, pkgErr := prj.FindPackageImport(names[0], sourceFile)
+		if pkgErr != nil {
+			return nil, err
+		}
+		names = names[1:]
+		sym = imp.FindSymbol(names[0])
+		if sym == nil {
+			return nil, errors.Errorf("symbol %v not found in %v", names[0], imp.Import)
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	sel, err := prj.selectPath(sym, names[1:])
+	if err != nil {
+		return nil, errors.Wrapf(err, "select %v", path)
+	}
+	return sel, nil
+}
+
+// walk through fields of types to the last member
+func (prj *Project) selectPath(sym *Symbol, names []string) (*Selection, error) {
+	sel := &Selection{Symbol: sym, Type: sym}
+	for i, name := range names {
+		if i > 0 {
+			// next member of type of the field
+			if sel.Field == nil {
+				return nil, errors.Errorf("method %v has no members", sel.Method.Name)
+			}
+			owner, err := namedType(prj, sel.Field.TypeExpr())
+			if err != nil {
+				return nil, errors.Wrapf(err, "type of field %v", sel.Field.Name)
+			}
+			sym = owner
+		}
+		member, err := selectMember(prj, sym, name)
+		if err != nil {
+			return nil, err
+		}
+		sel = member
+	}
+	return sel, nil
+}
+
+// named type of type expression: pointers and aliases are followed
+func namedType(resolver Resolver, typeExpr *Symbol) (*Symbol, error) {
+	current := typeExpr
+	for depth := 0; ; depth++ {
+		if depth > maxResolveDepth {
+			return nil, errors.Errorf("too deep type expression %v", typeExpr.Name)
+		}
+		switch v := current.Node.(type) {
+		case *ast.StarExpr:
+			current = current.TypeExpr(v.X)
+			continue
+		case *ast.ParenExpr:
+			current = current.TypeExpr(v.X)
+			continue
+		}
+		target, err := current.Unalias(resolver)
+		if err != nil {
+			return nil, err
+		}
+		if target == current || !isTypeLiteral(target) {
+			if !target.IsType() {
+				return nil, errors.Errorf("%v is not a named type", target.Name)
+			}
+			return target, nil
+		}
+		current = target
+	}
+}
+
+// method (including promoted) or field (including promoted) of named type
+func selectMember(resolver Resolver, sym *Symbol, name string) (*Selection, error) {
+	if !sym.IsType() {
+		return nil, errors.Errorf("%v is not a type", sym.Name)
+	}
+	methods, err := sym.MethodSet(resolver, true)
+	if err != nil {
+		return nil, err
+	}
+	var method *Method
+	for _, m := range methods {
+		if m.Name == name {
+			method = m
+		}
+	}
+	// the shallowest member wins, method set already excludes methods hidden by fields
+	field, path, err := findField(resolver, sym, name)
+	if method != nil && (field == nil || len(method.Path) < len(path)) {
+		return &Selection{Symbol: sym, Method: method, Type: method.signature(), Path: method.Path}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if field == nil {
+		return nil, errors.Errorf("%v has no method or field %v", sym.Name, name)
+	}
+	tp, err := field.TypeExpr().Resolve(resolver)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve type of field %v", name)
+	}
+	return &Selection{Symbol: sym, Field: field, Type: tp, Path: path}, nil
+}
+
+// field of struct or promoted field of embedded structs with the smallest depth
+func findField(resolver Resolver, owner *Symbol, name string) (*Field, []string, error) {
+	type item struct {
+		sym  *Symbol
+		path []string
+	}
+	var visited = make(map[string]bool)
+	level := []item{{sym: owner}}
+	for len(level) > 0 {
+		var (
+			next    []item
+			found   []item
+			field   *Field
+			reached []string
+		)
+		for _, it := range level {
+			sym, err := it.sym.Unalias(resolver)
+			if err != nil {
+				return nil, nil, err
+			}
+			// struct reached by several paths at the same depth is checked for every path
+			if !sym.IsStruct() || visited[sym.ID()] {
+				continue
+			}
+			reached = append(reached, sym.ID())
+			for _, p := range sym.Node.(*ast.TypeSpec).Type.(*ast.StructType).Fields.List {
+				embedded := embeddedName(p)
+				if len(p.Names) == 0 && embedded == name || hasName(p.Names, name) {
+					f, err := wrapField(p, sym, resolver)
+					if err != nil {
+						return nil, nil, err
+					}
+					f.Name = name
+					found = append(found, it)
+					field = f
+				}
+				if len(p.Names) > 0 {
+					continue
+				}
+				// fields of embedded types from not scanned packages are unknown
+				if tp, err := namedType(resolver, sym.TypeExpr(p.Type)); err == nil && !tp.BuiltIn {
+					next = append(next, item{sym: tp, path: append(append([]string{}, it.path...), embedded)})
+				}
+			}
+		}
+		for _, id := range reached {
+			visited[id] = true
+		}
+		switch {
+		case len(found) == 1:
+			return field, found[0].path, nil
+		case len(found) > 1:
+			return nil, nil, errors.Errorf("ambiguous selector %v", name)
+		}
+		level = next
+	}
+	return nil, nil, nil
+}
+
+// name of embedded field
+func embeddedName(p *ast.Field) string {
+	name := realTypeQN(p.Type)
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
+func hasName(names []*ast.Ident, name string) bool {
+	for _, ident := range names {
+		if ident.Name == name {
+			return true
+		}
+	}
+	return false
+}
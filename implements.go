@@ -0,0 +1,118 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"sort"
+)
+
+type Implementation struct {
+	Type    *Symbol
+	Pointer bool // only pointer to the type satisfies interface
+}
+
+// Check that type (pointer = false) or pointer to the type (pointer = true) satisfies interface.
+// Returns names of methods that are missing or have different signature.
+func (sym *Symbol) Implements(resolver Resolver, iface *Symbol, pointer bool) (bool, []string, error) {
+	required, err := iface.Methods(resolver)
+	if err != nil {
+		return false, nil, errors.Wrapf(err, "methods of %v", iface.Name)
+	}
+	methods, err := sym.MethodSet(resolver, pointer)
+	if err != nil {
+		return false, nil, errors.Wrapf(err, "method set of %v", sym.Name)
+	}
+	var byName = make(map[string]*Method, len(methods))
+	for _, m := range methods {
+		byName[m.Name] = m
+	}
+	var missing []string
+	for _, req := range required {
+		m, ok := byName[req.Name]
+		if !ok {
+			missing = append(missing, req.Name)
+			continue
+		}
+		same, err := identicalSignatures(resolver, m.signature(), req.signature())
+		if err != nil {
+			return false, nil, errors.Wrapf(err, "compare signatures of %v", req.Name)
+		}
+		if !same {
+			missing = append(missing, req.Name)
+		}
+	}
+	return len(missing) == 0, missing, nil
+}
+
+// Find all non-interface types in all scanned imports that satisfy interface (by value or by pointer).
+// Types which method sets can't be resolved (ex: not scanned embedded types) are reported by UncheckedTypesError
+// returned together with found implementations. Result sorted by import path and name.
+func (prj *Project) FindImplementations(iface *Symbol) ([]*Implementation, error) {
+	required, err := iface.Methods(prj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "methods of %v", iface.Name)
+	}
+	var (
+		ans       []*Implementation
+		unchecked = &UncheckedTypesError{}
+	)
+	for i := range prj.Imports {
+		imp := &prj.Imports[i]
+		err := imp.Symbols(func(sym *Symbol) error {
+			if !sym.IsType() || sym.IsInterface() || !mayImplement(sym, required) {
+				return nil
+			}
+			if ok, _, err := sym.Implements(prj, iface, false); err != nil {
+				unchecked.Types = append(unchecked.Types, sym)
+				unchecked.Errors = append(unchecked.Errors, err)
+				return nil
+			} else if ok {
+				ans = append(ans, &Implementation{Type: sym})
+				return nil
+			}
+			if ok, _, err := sym.Implements(prj, iface, true); err == nil && ok {
+				ans = append(ans, &Implementation{Type: sym, Pointer: true})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		a, b := ans[i].Type, ans[j].Type
+		if a.Import.Import != b.Import.Import {
+			return a.Import.Import < b.Import.Import
+		}
+		return a.Name < b.Name
+	})
+	if len(unchecked.Types) > 0 {
+		return ans, unchecked
+	}
+	return ans, nil
+}
+
+// fast check by declared methods names for types without embedded fields
+func mayImplement(sym *Symbol, required []*Method) bool {
+	if st, err := sym.structType(); err == nil {
+		var embedded bool
+		for _, f := range st.Fields.List {
+			if len(f.Names) != 0 {
+				continue
+			}
+			embedded = true
+		}
+		if embedded {
+			return true
+		}
+	}
+	var declared = make(map[string]bool)
+	for _, m := range sym.Import.MethodsOf(sym.Name) {
+		declared[m.Name] = true
+	}
+	for _, m := range required {
+		if !declared[m.Name] {
+			return false
+		}
+	}
+	return true
+}
@@ -32,10 +32,14 @@ func (imps Imports) ByPackageName(packageName string) *Import {
 	return nil
 }
 
-func (imps Imports) ByFile(f *File) Imports {
+func (imps Imports) ByFile(f *File) (Imports, error) {
+	names, err := f.Imports()
+	if err != nil {
+		return nil, err
+	}
 	var ans Imports
 	var mp = make(map[string]bool)
-	for _, n := range f.Imports() {
+	for _, n := range names {
 		mp[n] = true
 	}
 	for _, v := range imps {
@@ -43,7 +47,7 @@ func (imps Imports) ByFile(f *File) Imports {
 			ans = append(ans, v)
 		}
 	}
-	return ans
+	return ans, nil
 }
 
 func (imp *Import) FindSymbol(name string) *Symbol {
@@ -56,6 +60,69 @@ func (imp *Import) FindSymbol(name string) *Symbol {
 	return nil
 }
 
+// Methods declared for named type with value or pointer receiver
+func (imp *Import) MethodsOf(typeName string) []*Symbol {
+	index := imp.methods
+	if index == nil {
+		index = indexMethods(imp.Files)
+	}
+	var ans []*Symbol
+	for _, m := range index[typeName] {
+		ans = append(ans, &Symbol{Import: imp, File: m.File, Node: m.Decl, ParentNode: m.File.Ast, Name: m.Decl.Name.Name})
+	}
+	return ans
+}
+
+type methodDecl struct {
+	File *File
+	Decl *ast.FuncDecl
+}
+
+func indexMethods(files []*File) map[string][]methodDecl {
+	var index = make(map[string][]methodDecl)
+	for _, f := range files {
+		for _, decl := range f.Ast.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil {
+				continue
+			}
+			name, _ := receiverType(fn)
+			if name == "" {
+				continue
+			}
+			index[name] = append(index[name], methodDecl{File: f, Decl: fn})
+		}
+	}
+	return index
+}
+
+// base type name of receiver and is it a pointer receiver
+func receiverType(fn *ast.FuncDecl) (string, bool) {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return "", false
+	}
+	var pointer bool
+	expr := fn.Recv.List[0].Type
+	for {
+		switch v := expr.(type) {
+		case *ast.ParenExpr:
+			expr = v.X
+		case *ast.StarExpr:
+			pointer = true
+			expr = v.X
+		case *ast.IndexExpr:
+			// generic receiver
+			expr = v.X
+		case *ast.IndexListExpr:
+			expr = v.X
+		case *ast.Ident:
+			return v.Name, pointer
+		default:
+			return "", pointer
+		}
+	}
+}
+
 func (imp *Import) FindFile(name string) *File {
 	for _, f := range imp.Files {
 		if filepath.Base(f.Filename) == name {
@@ -101,7 +168,7 @@ func (f *File) FindSymbol(targetName string) *Node {
 				stack = append(stack, Node{Raw: spec, Parent: node.Raw})
 			}
 		case *ast.FuncDecl:
-			if v.Name.Name == targetName {
+			if v.Recv == nil && v.Name.Name == targetName {
 				return &node
 			}
 		case *ast.Ident:
@@ -167,7 +234,9 @@ func (f *File) SymbolsNames() []string {
 		case *ast.TypeSpec:
 			ans = append(ans, v.Name.Name)
 		case *ast.FuncDecl:
-			ans = append(ans, v.Name.Name)
+			if v.Recv == nil {
+				ans = append(ans, v.Name.Name)
+			}
 		case *ast.Ident:
 			ans = append(ans, v.Name)
 		case *ast.ValueSpec:
@@ -0,0 +1,496 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+)
+
+// infer type of value expression declared in scope of symbol. Result is index of value for
+// function calls with several results and comma-ok expressions.
+func (sym *Symbol) inferType(resolver Resolver, expr ast.Expr, result int, depth int) (*Symbol, error) {
+	if depth > maxResolveDepth {
+		return nil, errors.Errorf("too deep expression %v", sym.TypeExpr(expr).Name)
+	}
+	expr = unparen(expr)
+	if call, ok := expr.(*ast.CallExpr); ok {
+		return sym.inferCallType(resolver, call, result, depth)
+	}
+	if result == 1 && isCommaOk(expr) {
+		return sym.builtIn("bool"), nil
+	}
+	if result != 0 {
+		return nil, errors.Errorf("expression %v has single value", sym.TypeExpr(expr).Name)
+	}
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		return sym.builtIn(basicLitTypes[v.Kind]), nil
+	case *ast.CompositeLit:
+		if v.Type == nil {
+			return nil, errors.New("composite literal without type")
+		}
+		return sym.TypeExpr(v.Type).Resolve(resolver)
+	case *ast.FuncLit:
+		return sym.TypeExpr(v.Type), nil
+	case *ast.UnaryExpr:
+		switch v.Op {
+		case token.AND:
+			if lit, ok := unparen(v.X).(*ast.CompositeLit); ok && lit.Type != nil {
+				return sym.TypeExpr(&ast.StarExpr{Star: v.OpPos, X: lit.Type}), nil
+			}
+			tp, err := sym.inferType(resolver, v.X, 0, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			return pointerTo(tp)
+		case token.NOT:
+			return sym.builtIn("bool"), nil
+		case token.ADD, token.SUB, token.XOR:
+			return sym.inferType(resolver, v.X, 0, depth)
+		case token.ARROW:
+			ch, err := sym.inferUnderlying(resolver, v.X, depth)
+			if err != nil {
+				return nil, err
+			}
+			if c, ok := ch.Node.(*ast.ChanType); ok {
+				return ch.TypeExpr(c.Value).Resolve(resolver)
+			}
+		}
+	case *ast.BinaryExpr:
+		switch v.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			return sym.builtIn("bool"), nil
+		case token.SHL, token.SHR:
+			return sym.inferType(resolver, v.X, 0, depth)
+		}
+		// typed operand defines type of expression
+		if sym.isUntyped(resolver, v) {
+			// default type of the larger constant kind
+			return sym.builtIn(sym.untypedDefault(resolver, v)), nil
+		}
+		if sym.isUntyped(resolver, v.X) {
+			return sym.inferType(resolver, v.Y, 0, depth)
+		}
+		return sym.inferType(resolver, v.X, 0, depth)
+	case *ast.Ident:
+		if v.Obj != nil && v.Obj.Kind == ast.Var {
+			return sym.inferVarType(resolver, v, depth+1)
+		}
+		switch v.Name {
+		case "true", "false":
+			return sym.builtIn("bool"), nil
+		case "nil":
+			return nil, errors.New("untyped nil")
+		}
+		return sym.inferRefType(resolver, v.Name, depth)
+	case *ast.SelectorExpr:
+		if pkg, ok := v.X.(*ast.Ident); ok && pkg.Obj == nil {
+			ref, err := sym.inferRefType(resolver, realTypeQN(v), depth)
+			if err == nil {
+				return ref, nil
+			}
+			// package level variable declared in another file
+			if member, memberErr := sym.inferMemberType(resolver, v, depth); memberErr == nil {
+				return member, nil
+			}
+			return nil, err
+		}
+		return sym.inferMemberType(resolver, v, depth)
+	case *ast.StarExpr:
+		ptr, err := sym.inferUnderlying(resolver, v.X, depth)
+		if err != nil {
+			return nil, err
+		}
+		if star, ok := ptr.Node.(*ast.StarExpr); ok {
+			return ptr.TypeExpr(star.X).Resolve(resolver)
+		}
+	case *ast.TypeAssertExpr:
+		if v.Type != nil {
+			return sym.TypeExpr(v.Type).Resolve(resolver)
+		}
+	case *ast.IndexExpr:
+		container, err := sym.inferUnderlying(resolver, v.X, depth)
+		if err != nil {
+			return nil, err
+		}
+		if star, ok := container.Node.(*ast.StarExpr); ok {
+			// pointer to array
+			if container, err = container.TypeExpr(star.X).Underlying(resolver); err != nil {
+				return nil, err
+			}
+		}
+		switch c := container.Node.(type) {
+		case *ast.ArrayType:
+			return container.TypeExpr(c.Elt).Resolve(resolver)
+		case *ast.MapType:
+			return container.TypeExpr(c.Value).Resolve(resolver)
+		}
+		if isString(container) {
+			return sym.builtIn("byte"), nil
+		}
+	case *ast.SliceExpr:
+		container, err := sym.inferType(resolver, v.X, 0, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		underlying, err := container.Underlying(resolver)
+		if err != nil {
+			return nil, err
+		}
+		if star, ok := underlying.Node.(*ast.StarExpr); ok {
+			// pointer to array
+			if underlying, err = underlying.TypeExpr(star.X).Underlying(resolver); err != nil {
+				return nil, err
+			}
+		}
+		if arr, ok := underlying.Node.(*ast.ArrayType); ok && arr.Len != nil {
+			return underlying.TypeExpr(&ast.ArrayType{Lbrack: arr.Lbrack, Elt: arr.Elt}), nil
+		}
+		// slices and strings
+		return container, nil
+	}
+	return nil, errors.Errorf("can not infer type of expression %v", sym.TypeExpr(expr).Name)
+}
+
+// underlying type of value expression
+func (sym *Symbol) inferUnderlying(resolver Resolver, expr ast.Expr, depth int) (*Symbol, error) {
+	tp, err := sym.inferType(resolver, expr, 0, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	return tp.Underlying(resolver)
+}
+
+// type of parameter, receiver, local variable or package level variable declared in the same file
+func (sym *Symbol) inferVarType(resolver Resolver, ident *ast.Ident, depth int) (*Symbol, error) {
+	switch decl := ident.Obj.Decl.(type) {
+	case *ast.Field:
+		return sym.TypeExpr(decl.Type).Resolve(resolver)
+	case *ast.ValueSpec:
+		if decl.Type != nil {
+			return sym.TypeExpr(decl.Type).Resolve(resolver)
+		}
+		for i, name := range decl.Names {
+			if name.Name == ident.Name {
+				return sym.inferAssigned(resolver, decl.Values, i, len(decl.Names), depth)
+			}
+		}
+	case *ast.AssignStmt:
+		for i, lhs := range decl.Lhs {
+			if name, ok := lhs.(*ast.Ident); !ok || name.Name != ident.Name {
+				continue
+			}
+			if rng, ok := decl.Rhs[0].(*ast.UnaryExpr); ok && rng.Op == token.RANGE {
+				return sym.inferRangeType(resolver, rng.X, i, depth)
+			}
+			return sym.inferAssigned(resolver, decl.Rhs, i, len(decl.Lhs), depth)
+		}
+	}
+	return nil, errors.Errorf("can not infer type of variable %v", ident.Name)
+}
+
+// type of value with index in declaration or assignment of several names
+func (sym *Symbol) inferAssigned(resolver Resolver, values []ast.Expr, index int, names int, depth int) (*Symbol, error) {
+	switch {
+	case len(values) == names:
+		return sym.inferType(resolver, values[index], 0, depth)
+	case len(values) == 1:
+		// multiple results of function call or comma-ok expression
+		return sym.inferType(resolver, values[0], index, depth)
+	}
+	return nil, errors.New("variable has neither type nor value")
+}
+
+// type of key (index = 0) or value (index = 1) of range clause
+func (sym *Symbol) inferRangeType(resolver Resolver, expr ast.Expr, index int, depth int) (*Symbol, error) {
+	container, err := sym.inferUnderlying(resolver, expr, depth)
+	if err != nil {
+		return nil, err
+	}
+	if star, ok := container.Node.(*ast.StarExpr); ok {
+		// pointer to array
+		if container, err = container.TypeExpr(star.X).Underlying(resolver); err != nil {
+			return nil, err
+		}
+	}
+	switch c := container.Node.(type) {
+	case *ast.ArrayType:
+		if index == 0 {
+			return sym.builtIn("int"), nil
+		}
+		return container.TypeExpr(c.Elt).Resolve(resolver)
+	case *ast.MapType:
+		if index == 0 {
+			return container.TypeExpr(c.Key).Resolve(resolver)
+		}
+		return container.TypeExpr(c.Value).Resolve(resolver)
+	case *ast.ChanType:
+		if index == 0 {
+			return container.TypeExpr(c.Value).Resolve(resolver)
+		}
+	}
+	switch {
+	case isString(container) && index == 0:
+		return sym.builtIn("int"), nil
+	case isString(container):
+		return sym.builtIn("rune"), nil
+	case container.BuiltIn && index == 0:
+		// range over integer
+		return sym.inferType(resolver, expr, 0, depth+1)
+	}
+	return nil, errors.Errorf("can not infer type of range over %v", container.Name)
+}
+
+// type of field or method value selected from value
+func (sym *Symbol) inferMemberType(resolver Resolver, sel *ast.SelectorExpr, depth int) (*Symbol, error) {
+	owner, err := sym.inferType(resolver, sel.X, 0, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	named, err := namedType(resolver, owner)
+	if err != nil {
+		return nil, err
+	}
+	member, err := selectMember(resolver, named, sel.Sel.Name)
+	if err != nil {
+		return nil, err
+	}
+	return member.Type, nil
+}
+
+// expression is untyped constant: literal, predeclared or untyped named constant or operation on them
+func (sym *Symbol) isUntyped(resolver Resolver, expr ast.Expr) bool {
+	switch v := unparen(expr).(type) {
+	case *ast.BasicLit:
+		return true
+	case *ast.UnaryExpr:
+		return v.Op != token.AND && v.Op != token.ARROW && sym.isUntyped(resolver, v.X)
+	case *ast.BinaryExpr:
+		if v.Op == token.SHL || v.Op == token.SHR {
+			return sym.isUntyped(resolver, v.X)
+		}
+		return sym.isUntyped(resolver, v.X) && sym.isUntyped(resolver, v.Y)
+	case *ast.Ident:
+		if v.Obj != nil && v.Obj.Kind != ast.Con {
+			return false
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := v.X.(*ast.Ident); !ok || pkg.Obj != nil {
+			return false
+		}
+	default:
+		return false
+	}
+	ref, err := resolver.FindSymbol(realTypeQN(unparen(expr)), sym.File)
+	if err != nil {
+		return false
+	}
+	if ref.BuiltIn {
+		return ref.Universe == UniverseConst
+	}
+	if !ref.IsConstant() {
+		return false
+	}
+	c, err := ref.ConstValue(resolver)
+	return err == nil && c.Type == nil
+}
+
+// name of default type of untyped constant expression
+func (sym *Symbol) untypedDefault(resolver Resolver, expr ast.Expr) string {
+	switch v := unparen(expr).(type) {
+	case *ast.BasicLit:
+		return basicLitTypes[v.Kind]
+	case *ast.UnaryExpr:
+		if v.Op == token.NOT {
+			return "bool"
+		}
+		return sym.untypedDefault(resolver, v.X)
+	case *ast.BinaryExpr:
+		switch v.Op {
+		case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ, token.LAND, token.LOR:
+			return "bool"
+		case token.SHL, token.SHR:
+			return sym.untypedDefault(resolver, v.X)
+		}
+		x, y := sym.untypedDefault(resolver, v.X), sym.untypedDefault(resolver, v.Y)
+		if untypedOrder[y] > untypedOrder[x] {
+			return y
+		}
+		return x
+	}
+	ref, err := resolver.FindSymbol(realTypeQN(unparen(expr)), sym.File)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case ref.BuiltIn && ref.Basic == types.UntypedBool:
+		return "bool"
+	case ref.BuiltIn && ref.Basic == types.UntypedInt:
+		// iota
+		return "int"
+	case ref.BuiltIn:
+		return ""
+	}
+	c, err := ref.ConstValue(resolver)
+	if err != nil {
+		return ""
+	}
+	return defaultConstTypes[c.Value.Kind()]
+}
+
+// order of numeric kinds of untyped constants
+var untypedOrder = map[string]int{
+	"int":        1,
+	"rune":       2,
+	"float64":    3,
+	"complex128": 4,
+}
+
+// type of referenced variable, constant or function
+func (sym *Symbol) inferRefType(resolver Resolver, name string, depth int) (*Symbol, error) {
+	ref, err := resolver.FindSymbol(name, sym.File)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ref.IsVariable():
+		return ref.varType(resolver, depth+1)
+	case ref.IsConstant():
+		c, err := ref.ConstValue(resolver)
+		if err != nil {
+			return nil, err
+		}
+		if c.Type != nil {
+			return c.Type, nil
+		}
+		return ref.builtIn(defaultConstTypes[c.Value.Kind()]), nil
+	case ref.IsFunction():
+		return ref.TypeExpr(ref.Node.(*ast.FuncDecl).Type), nil
+	}
+	return nil, errors.Errorf("%v is not a value", name)
+}
+
+// type of conversion, result of function or built-in function call
+func (sym *Symbol) inferCallType(resolver Resolver, call *ast.CallExpr, result int, depth int) (*Symbol, error) {
+	fun := unparen(call.Fun)
+	switch v := fun.(type) {
+	case *ast.ArrayType, *ast.MapType, *ast.ChanType, *ast.FuncType, *ast.InterfaceType, *ast.StarExpr:
+		return sym.TypeExpr(fun), nil
+	case *ast.Ident:
+		if builtIn, err := resolver.FindSymbol(v.Name, sym.File); err != nil || !builtIn.BuiltIn {
+			break
+		}
+		switch v.Name {
+		case "new":
+			if len(call.Args) == 1 {
+				return sym.TypeExpr(&ast.StarExpr{Star: call.Pos(), X: call.Args[0]}), nil
+			}
+		case "make":
+			if len(call.Args) > 0 {
+				return sym.TypeExpr(call.Args[0]).Resolve(resolver)
+			}
+		case "len", "cap", "copy":
+			return sym.builtIn("int"), nil
+		case "append":
+			if len(call.Args) > 0 {
+				return sym.inferType(resolver, call.Args[0], 0, depth)
+			}
+		}
+	}
+	if root := rootIdent(fun); root == nil || root.Obj == nil || root.Obj.Kind != ast.Var {
+		if target, err := sym.TypeExpr(fun).Resolve(resolver); err == nil {
+			if (target.BuiltIn && target.Universe == UniverseType) || target.IsType() {
+				// conversion
+				return target, nil
+			}
+			if fn, ok := target.Node.(*ast.FuncDecl); ok {
+				return inferResultType(resolver, target, fn.Type, result)
+			}
+		}
+	}
+	// method or function value
+	tp, err := sym.inferUnderlying(resolver, fun, depth)
+	if err != nil {
+		return nil, err
+	}
+	fn, ok := tp.Node.(*ast.FuncType)
+	if !ok {
+		return nil, errors.Errorf("%v is not a function or type", sym.TypeExpr(fun).Name)
+	}
+	return inferResultType(resolver, tp, fn, result)
+}
+
+func inferResultType(resolver Resolver, fn *Symbol, fnType *ast.FuncType, result int) (*Symbol, error) {
+	results := fieldTypes(fnType.Results)
+	if result >= len(results) {
+		return nil, errors.Errorf("function %v has %v results", fn.Name, len(results))
+	}
+	return fn.TypeExpr(results[result]).Resolve(resolver)
+}
+
+// pointer to type
+func pointerTo(tp *Symbol) (*Symbol, error) {
+	switch v := tp.Node.(type) {
+	case ast.Expr:
+		if !tp.TypeParam {
+			return tp.TypeExpr(&ast.StarExpr{X: v}), nil
+		}
+	case *ast.TypeSpec:
+		if len(tp.TypeArgs) == 0 {
+			return tp.TypeExpr(&ast.StarExpr{X: v.Name}), nil
+		}
+	}
+	return nil, errors.Errorf("can not make pointer to %v", tp.Name)
+}
+
+// identifier at the beginning of selector or index expression
+func rootIdent(expr ast.Expr) *ast.Ident {
+	switch v := unparen(expr).(type) {
+	case *ast.Ident:
+		return v
+	case *ast.SelectorExpr:
+		return rootIdent(v.X)
+	case *ast.IndexExpr:
+		return rootIdent(v.X)
+	case *ast.IndexListExpr:
+		return rootIdent(v.X)
+	}
+	return nil
+}
+
+// expression has optional second boolean value: v, ok = x.(T), v, ok = m[k] or v, ok = <-ch
+func isCommaOk(expr ast.Expr) bool {
+	switch v := expr.(type) {
+	case *ast.TypeAssertExpr, *ast.IndexExpr:
+		return true
+	case *ast.UnaryExpr:
+		return v.Op == token.ARROW
+	}
+	return false
+}
+
+func isString(underlying *Symbol) bool {
+	return underlying.BuiltIn && types.Typ[underlying.Basic].Info()&types.IsString != 0
+}
+
+func (sym *Symbol) builtIn(name string) *Symbol {
+	return universeSymbol(name, sym.File)
+}
+
+var basicLitTypes = map[token.Token]string{
+	token.INT:    "int",
+	token.FLOAT:  "float64",
+	token.IMAG:   "complex128",
+	token.CHAR:   "rune",
+	token.STRING: "string",
+}
+
+var defaultConstTypes = map[constant.Kind]string{
+	constant.Bool:    "bool",
+	constant.String:  "string",
+	constant.Int:     "int",
+	constant.Float:   "float64",
+	constant.Complex: "complex128",
+}
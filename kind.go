@@ -0,0 +1,77 @@
+package symbols
+
+import (
+	"go/ast"
+)
+
+// Kind of symbol
+type Kind int
+
+const (
+	KindUnknown   Kind = iota // type expressions, literals and other nodes
+	KindType                  // named type which is not a struct, interface or alias
+	KindStruct                // named struct type
+	KindInterface             // named interface type
+	KindAlias                 // type alias
+	KindFunc                  // function without receiver
+	KindMethod                // function with receiver
+	KindVar                   // variable
+	KindConst                 // constant
+	KindBuiltin               // predeclared identifier
+	KindPackage               // imported or scanned package (see Project.FindPackageSymbol and Project.Lookup)
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindType:
+		return "type"
+	case KindStruct:
+		return "struct"
+	case KindInterface:
+		return "interface"
+	case KindAlias:
+		return "alias"
+	case KindFunc:
+		return "func"
+	case KindMethod:
+		return "method"
+	case KindVar:
+		return "var"
+	case KindConst:
+		return "const"
+	case KindBuiltin:
+		return "builtin"
+	case KindPackage:
+		return "package"
+	}
+	return "unknown"
+}
+
+// Kind of symbol. Type parameters are reported as types. Safe for nil symbols.
+func (sym *Symbol) Kind() Kind {
+	switch {
+	case sym == nil:
+		return KindUnknown
+	case sym.BuiltIn:
+		return KindBuiltin
+	case sym.TypeParam:
+		return KindType
+	}
+	return NodeKind(sym.Node)
+}
+
+// node of symbol, nil for nil symbol
+func (sym *Symbol) node() ast.Node {
+	if sym == nil {
+		return nil
+	}
+	return sym.Node
+}
+
+// import path of symbol, empty for symbols without import
+func (sym *Symbol) importPath() string {
+	if sym == nil || sym.Import == nil {
+		return ""
+	}
+	return sym.Import.Import
+}
@@ -0,0 +1,147 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"sort"
+)
+
+type promotion struct {
+	Symbol  *Symbol
+	Pointer bool // methods with pointer receiver are accessible
+	Path    []string
+}
+
+// Method set of named type (pointer = false) or of pointer to the type (pointer = true) including methods
+// promoted from embedded fields. Methods are sorted by name.
+// For interfaces it is the same as Methods.
+func (sym *Symbol) MethodSet(resolver Resolver, pointer bool) ([]*Method, error) {
+	if sym.IsAlias() {
+		target, err := sym.Unalias(resolver)
+		if err != nil {
+			return nil, err
+		}
+		if target.IsType() {
+			return target.MethodSet(resolver, pointer)
+		}
+		return nil, nil
+	}
+	if sym.IsInterface() {
+		return sym.Methods(resolver)
+	}
+	if !sym.IsType() {
+		return nil, errors.New("is not a type")
+	}
+	var (
+		ans     []*Method
+		shadow  = make(map[string]bool)
+		visited = make(map[string]bool)
+		level   = []promotion{{Symbol: sym, Pointer: pointer}}
+	)
+	for len(level) > 0 {
+		var (
+			next       []promotion
+			reached    []string
+			candidates = make(map[string][]*Method)
+			names      []string
+		)
+		add := func(name string, method *Method) {
+			if _, ok := candidates[name]; !ok {
+				names = append(names, name)
+			}
+			candidates[name] = append(candidates[name], method)
+		}
+		for _, item := range level {
+			if item.Symbol.BuiltIn {
+				// embedded error
+				method := errorMethod(item.Symbol)
+				method.Path = item.Path
+				add(method.Name, method)
+				continue
+			}
+			// type reached by several paths at the same depth is processed for every path to find
+			// ambiguous selectors, types from lower depths are hidden by the shallower ones
+			key := item.Symbol.Import.Import + "." + item.Symbol.Name
+			if visited[key] {
+				continue
+			}
+			reached = append(reached, key)
+
+			if item.Symbol.IsInterface() {
+				methods, err := item.Symbol.Methods(resolver)
+				if err != nil {
+					return nil, errors.Wrapf(err, "methods of embedded %v", item.Symbol.Name)
+				}
+				for _, m := range methods {
+					promoted := *m
+					promoted.Path = item.Path
+					add(m.Name, &promoted)
+				}
+				continue
+			}
+
+			for _, decl := range item.Symbol.Import.MethodsOf(item.Symbol.Name) {
+				if len(item.Symbol.TypeArgs) > 0 && len(typeParamIdents(decl.Node)) == len(item.Symbol.TypeArgs) {
+					// receiver type parameters are arguments of instantiated type
+					decl.TypeArgs = item.Symbol.TypeArgs
+				}
+				fn, err := decl.Function()
+				if err != nil {
+					return nil, err
+				}
+				if fn.PointerReceiver && !item.Pointer {
+					continue
+				}
+				add(fn.Name, &Method{Name: fn.Name, RawCall: fn.Raw.Type, Function: fn, Path: item.Path, File: fn.File, scope: decl})
+			}
+
+			if !item.Symbol.IsStruct() {
+				continue
+			}
+			// fields with the same name hide methods at the same and deeper levels
+			st, err := item.Symbol.structType()
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range flatFields(st.Fields) {
+				if f.name != "" {
+					add(f.name, nil)
+				}
+			}
+			embedded, err := item.Symbol.EmbeddedFields(resolver)
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range embedded {
+				add(field.Name, nil)
+				if field.Type.BuiltIn && field.Type.Name != "error" {
+					continue
+				}
+				path := append(append([]string{}, item.Path...), field.Name)
+				next = append(next, promotion{
+					Symbol:  field.Type,
+					Pointer: item.Pointer || IsPointer(field.RawType),
+					Path:    path,
+				})
+			}
+		}
+		for _, key := range reached {
+			visited[key] = true
+		}
+		for _, name := range names {
+			if shadow[name] {
+				continue
+			}
+			shadow[name] = true
+			found := candidates[name]
+			// ambiguous selectors at the same depth are not promoted
+			if len(found) == 1 && found[0] != nil {
+				ans = append(ans, found[0])
+			}
+		}
+		level = next
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		return ans[i].Name < ans[j].Name
+	})
+	return ans, nil
+}
@@ -13,6 +13,8 @@ type Resolver interface {
 type Project struct {
 	Imports Imports
 	Package *Import
+	lookups []string          // folders to find sources of not scanned imports
+	names   map[string]string // cache of package names of not scanned imports
 }
 
 func ProjectByPackage(packageImport string, limit int) (*Project, error) {
@@ -22,11 +24,12 @@ func ProjectByPackage(packageImport string, limit int) (*Project, error) {
 	}
 	imp := imps.ByImport(packageImport)
 	if imp == nil {
-		panic("import scanned but not found")
+		return nil, errors.Wrap(ErrImportNotFound, packageImport)
 	}
 	return &Project{
 		Imports: imps,
 		Package: imp,
+		lookups: lookupFolders("."),
 	}, nil
 }
 
@@ -42,49 +45,100 @@ func ProjectByDir(location string, limit int) (*Project, error) {
 	return &Project{
 		Imports: imps,
 		Package: imps.ByImport(pkg),
+		lookups: lookupFolders(location),
 	}, nil
 }
 
+// Find import in file by alias or by package name. Blank and dot imports are ignored.
+// Not scanned imports are matched by package name declared in sources (if they could be found) or by name
+// derived from import path and returned without files.
 func (prj *Project) FindPackageImport(packageNameOrAlias string, file *File) (*Import, error) {
-	// find by alias
-	imps := prj.Imports.ByFile(file)
 	for _, imp := range file.Ast.Imports {
-		alias := ""
+		importPath, err := importPath(file.FileSet, imp)
+		if err != nil {
+			return nil, err
+		}
+		var name string
 		if imp.Name != nil {
-			alias = imp.Name.Name
+			if imp.Name.Name == "_" || imp.Name.Name == "." {
+				continue
+			}
+			name = imp.Name.Name
+		} else {
+			name = prj.packageName(importPath)
 		}
-		if alias == packageNameOrAlias {
-			importPath, _ := strconv.Unquote(imp.Path.Value)
-			return imps.ByImport(importPath), nil
+		if name == packageNameOrAlias {
+			return prj.importByPath(importPath), nil
 		}
 	}
-	// find by package
-	imp := prj.Imports.ByFile(file).ByPackageName(packageNameOrAlias)
-	if imp != nil {
-		return imp, nil
-	}
 	return nil, errors.Errorf("failed to resolve import by package or alias %v", packageNameOrAlias)
 }
 
+// Symbol of package imported in file by package name or alias: Node is import specification, File is the
+// importing file and Import is the imported package
+func (prj *Project) FindPackageSymbol(packageNameOrAlias string, file *File) (*Symbol, error) {
+	imp, err := prj.FindPackageImport(packageNameOrAlias, file)
+	if err != nil {
+		return nil, err
+	}
+	for _, spec := range file.Ast.Imports {
+		path, err := importPath(file.FileSet, spec)
+		if err != nil || path != imp.Import || spec.Name != nil && spec.Name.Name != packageNameOrAlias {
+			continue
+		}
+		return &Symbol{Import: imp, File: file, Node: spec, Name: packageNameOrAlias}, nil
+	}
+	return nil, errors.Wrap(ErrImportNotFound, imp.Import)
+}
+
+// Imports of file imported with dot
+func (prj *Project) DotImports(file *File) []*Import {
+	var ans []*Import
+	for _, imp := range file.Ast.Imports {
+		if imp.Name == nil || imp.Name.Name != "." {
+			continue
+		}
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			continue
+		}
+		ans = append(ans, prj.importByPath(importPath))
+	}
+	return ans
+}
+
 func (prj *Project) FindSymbol(qualifiedName string, sourceFile *File) (*Symbol, error) {
 	// unref
-	if builtinTypes[qualifiedName] {
-		return &Symbol{Name: qualifiedName, File: sourceFile, Node: nil, Import: nil, BuiltIn: true}, nil
-	}
 	qualifiedName = strings.Replace(qualifiedName, "*", "", -1)
 	parts := strings.Split(qualifiedName, ".")
+	if len(parts) > 2 {
+		return nil, errors.Errorf("%v is not a qualified name (use FindMember for members)", qualifiedName)
+	}
+	name := parts[len(parts)-1]
 	var lookupImport *Import
 	if len(parts) == 1 {
 		// in current file package
-		lookupImport = prj.Imports.ByImport(sourceFile.Import)
-	} else {
-		imp, err := prj.FindPackageImport(parts[0], sourceFile)
-		if err != nil {
-			return nil, err
+		lookupImport = prj.importByPath(sourceFile.Import)
+		if sym := lookupImport.FindSymbol(name); sym != nil {
+			return sym, nil
+		}
+		// in packages imported with dot
+		for _, imp := range prj.DotImports(sourceFile) {
+			if sym := imp.FindSymbol(name); sym != nil {
+				return sym, nil
+			}
 		}
-		lookupImport = imp
+		// predeclared identifiers can be shadowed by declarations of package
+		if sym := universeSymbol(name, sourceFile); sym != nil {
+			return sym, nil
+		}
+		return nil, errors.Errorf("symbol %v not found in %v", name, lookupImport.Import)
 	}
-	name := parts[len(parts)-1]
+	imp, err := prj.FindPackageImport(parts[0], sourceFile)
+	if err != nil {
+		return nil, err
+	}
+	lookupImport = imp
 	sym := lookupImport.FindSymbol(name)
 	if sym == nil {
 		return nil, errors.Errorf("symbol %v not found in %v", name, lookupImport.Import)
@@ -92,6 +146,33 @@ func (prj *Project) FindSymbol(qualifiedName string, sourceFile *File) (*Symbol,
 	return sym, nil
 }
 
+// scanned import or import without files
+func (prj *Project) importByPath(importPath string) *Import {
+	if imp := prj.Imports.ByImport(importPath); imp != nil {
+		return imp
+	}
+	return &Import{Import: importPath, Package: prj.packageName(importPath)}
+}
+
+// name of package by import path: from scanned import, from package clause of sources or derived from path
+func (prj *Project) packageName(importPath string) string {
+	if imp := prj.Imports.ByImport(importPath); imp != nil {
+		return imp.Package
+	}
+	if name, ok := prj.names[importPath]; ok {
+		return name
+	}
+	name := findPackageName(importPath, prj.lookups...)
+	if name == "" {
+		name = packageNameByPath(importPath)
+	}
+	if prj.names == nil {
+		prj.names = make(map[string]string)
+	}
+	prj.names[importPath] = name
+	return name
+}
+
 // Find symbol defined only in the package
 func (prj *Project) FindLocalSymbol(name string) (*Symbol, error) {
 	for _, v := range prj.Package.Files {
@@ -5,8 +5,12 @@ import (
 	"go/ast"
 )
 
+// Element of array or slice type, nil for other nodes
 func ArrayItem(node ast.Node) ast.Node {
-	v := node.(*ast.ArrayType)
+	v, ok := node.(*ast.ArrayType)
+	if !ok {
+		return nil
+	}
 	return v.Elt
 }
 
@@ -15,6 +19,7 @@ func IsIdent(node ast.Node) bool {
 	return ok
 }
 
+// Is declaration of function or method (use IsMethod or NodeKind to distinguish them)
 func IsFunction(node ast.Node) bool {
 	_, ok := node.(*ast.FuncDecl)
 	return ok
@@ -31,17 +36,16 @@ func IsPointer(node ast.Node) bool {
 }
 
 func IsStruct(node ast.Node) bool {
-	if !IsType(node) {
+	v, ok := node.(*ast.TypeSpec)
+	if !ok {
 		return false
 	}
-	_, ok := (node.(*ast.TypeSpec)).Type.(*ast.StructType)
+	_, ok = v.Type.(*ast.StructType)
 	return ok
 }
 
+// Is struct type literal (struct{...})
 func IsStructDefinition(node ast.Node) bool {
-	if !IsType(node) {
-		return false
-	}
 	_, ok := node.(*ast.StructType)
 	return ok
 }
@@ -52,33 +56,33 @@ func IsMap(node ast.Node) bool {
 }
 
 func IsInterface(node ast.Node) bool {
-	if !IsType(node) {
+	v, ok := node.(*ast.TypeSpec)
+	if !ok {
 		return false
 	}
-	_, ok := (node.(*ast.TypeSpec)).Type.(*ast.InterfaceType)
+	_, ok = v.Type.(*ast.InterfaceType)
 	return ok
 }
 
+func IsAlias(node ast.Node) bool {
+	v, ok := node.(*ast.TypeSpec)
+	return ok && v.Assign.IsValid()
+}
+
+// Is function declared with receiver
+func IsMethod(node ast.Node) bool {
+	v, ok := node.(*ast.FuncDecl)
+	return ok && v.Recv != nil
+}
+
 func IsVariable(node ast.Node) bool {
 	v, ok := node.(*ast.Ident)
-	if !ok {
-		return false
-	}
-	if v.Obj.Kind != ast.Var {
-		return false
-	}
-	return true
+	return ok && v.Obj != nil && v.Obj.Kind == ast.Var
 }
 
 func IsConstant(node ast.Node) bool {
 	v, ok := node.(*ast.Ident)
-	if !ok {
-		return false
-	}
-	if v.Obj.Kind != ast.Con {
-		return false
-	}
-	return true
+	return ok && v.Obj != nil && v.Obj.Kind == ast.Con
 }
 
 func IsCall(node ast.Node) bool {
@@ -103,3 +107,34 @@ func IsType(node ast.Node) bool {
 	_, ok := node.(*ast.TypeSpec)
 	return ok
 }
+
+// Kind of declaration node. Built-in symbols has no nodes and can't be detected by the function.
+func NodeKind(node ast.Node) Kind {
+	switch v := node.(type) {
+	case *ast.TypeSpec:
+		switch {
+		case v.Assign.IsValid():
+			return KindAlias
+		case IsStruct(v):
+			return KindStruct
+		case IsInterface(v):
+			return KindInterface
+		}
+		return KindType
+	case *ast.FuncDecl:
+		if v.Recv != nil {
+			return KindMethod
+		}
+		return KindFunc
+	case *ast.Ident:
+		switch {
+		case IsVariable(v):
+			return KindVar
+		case IsConstant(v):
+			return KindConst
+		}
+	case *ast.ImportSpec, *ast.File:
+		return KindPackage
+	}
+	return KindUnknown
+}
@@ -0,0 +1,191 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/token"
+	"sort"
+)
+
+type References struct {
+	File      *File
+	Positions []token.Position
+}
+
+// Find usages of type, function, constant or variable declared on package level in all scanned files:
+// type references in fields and signatures, composite literals, calls and qualified identifiers
+// (including aliased and dot imports). Declaration itself is not included. Result grouped by files
+// and sorted by file names.
+func (prj *Project) References(sym *Symbol) ([]*References, error) {
+	var target *ast.Object
+	switch v := sym.Node.(type) {
+	case *ast.TypeSpec:
+		target = v.Name.Obj
+	case *ast.FuncDecl:
+		if v.Recv != nil {
+			return nil, errors.Errorf("references of method %v are not supported", sym.Name)
+		}
+		target = v.Name.Obj
+	case *ast.Ident:
+		if !sym.IsVariable() && !sym.IsConstant() {
+			return nil, errors.Errorf("%v is not a declaration", sym.Name)
+		}
+		target = v.Obj
+	default:
+		return nil, errors.Errorf("%v is not a declaration", sym.Name)
+	}
+	if sym.Import == nil {
+		return nil, errors.Errorf("%v is not declared in package", sym.Name)
+	}
+	var ans []*References
+	for i := range prj.Imports {
+		imp := &prj.Imports[i]
+		for _, file := range imp.Files {
+			finder := &refFinder{
+				project:  prj,
+				file:     file,
+				name:     sym.Name,
+				pkg:      sym.Import.Import,
+				target:   target,
+				local:    imp.Import == sym.Import.Import,
+				packages: make(map[string]string),
+			}
+			for _, dot := range prj.DotImports(file) {
+				finder.local = finder.local || dot.Import == sym.Import.Import
+			}
+			ast.Inspect(file.Ast, finder.visit)
+			if len(finder.found) == 0 {
+				continue
+			}
+			refs := &References{File: file}
+			for _, ident := range finder.found {
+				refs.Positions = append(refs.Positions, file.Position(ident.Pos()))
+			}
+			ans = append(ans, refs)
+		}
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		return ans[i].File.Filename < ans[j].File.Filename
+	})
+	return ans, nil
+}
+
+type refFinder struct {
+	project  *Project
+	file     *File
+	name     string      // name of declaration
+	pkg      string      // import path of declaration
+	target   *ast.Object // object of declaration in own file
+	local    bool        // declaration is visible without qualifier (same package or dot import)
+	packages map[string]string
+	found    []*ast.Ident
+}
+
+func (rf *refFinder) visit(node ast.Node) bool {
+	switch v := node.(type) {
+	case *ast.ImportSpec:
+		return false
+	case *ast.FuncDecl:
+		// name of function is declaration, not usage
+		rf.walk(v.Recv, v.Type, v.Body)
+		return false
+	case *ast.TypeSpec:
+		rf.walk(v.TypeParams, v.Type)
+		return false
+	case *ast.ValueSpec:
+		rf.walk(v.Type)
+		for _, value := range v.Values {
+			rf.walk(value)
+		}
+		return false
+	case *ast.SelectorExpr:
+		if x, ok := v.X.(*ast.Ident); ok && x.Obj == nil {
+			if importPath, isPackage := rf.packageOf(x.Name); isPackage {
+				if importPath == rf.pkg && v.Sel.Name == rf.name {
+					rf.found = append(rf.found, v.Sel)
+				}
+				return false
+			}
+		}
+		// selected field or method is not a package level declaration
+		rf.walk(v.X)
+		return false
+	case *ast.CompositeLit:
+		rf.walk(v.Type)
+		for _, elt := range v.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				rf.walk(elt)
+				continue
+			}
+			if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != rf.name || !rf.isStructLit(v) {
+				rf.walk(kv.Key)
+			}
+			// keys of struct literals are field names
+			rf.walk(kv.Value)
+		}
+		return false
+	case *ast.Ident:
+		if v.Name != rf.name {
+			return true
+		}
+		if (v.Obj != nil && v.Obj == rf.target) || (v.Obj == nil && rf.local) {
+			rf.found = append(rf.found, v)
+		}
+	}
+	return true
+}
+
+func (rf *refFinder) walk(nodes ...ast.Node) {
+	for _, node := range nodes {
+		if node != nil && !isNilNode(node) {
+			ast.Inspect(node, rf.visit)
+		}
+	}
+}
+
+// import path of package by name or alias in file
+func (rf *refFinder) packageOf(name string) (string, bool) {
+	if importPath, ok := rf.packages[name]; ok {
+		return importPath, importPath != ""
+	}
+	var importPath string
+	if imp, err := rf.project.FindPackageImport(name, rf.file); err == nil {
+		importPath = imp.Import
+	}
+	rf.packages[name] = importPath
+	return importPath, importPath != ""
+}
+
+// composite literal of struct type (literals with elided or unknown types are assumed to be structs)
+func (rf *refFinder) isStructLit(lit *ast.CompositeLit) bool {
+	if lit.Type == nil {
+		return true
+	}
+	switch lit.Type.(type) {
+	case *ast.ArrayType, *ast.MapType:
+		return false
+	case *ast.StructType:
+		return true
+	}
+	imp := rf.project.importByPath(rf.file.Import)
+	underlying, err := typeExprSymbol(lit.Type, rf.file, imp).Underlying(rf.project)
+	if err != nil {
+		return true
+	}
+	_, ok := underlying.Node.(*ast.StructType)
+	return ok
+}
+
+// typed nil pointers of optional nodes (ex: *ast.BlockStmt of function without body)
+func isNilNode(node ast.Node) bool {
+	switch v := node.(type) {
+	case *ast.FieldList:
+		return v == nil
+	case *ast.BlockStmt:
+		return v == nil
+	case *ast.FuncType:
+		return v == nil
+	}
+	return false
+}
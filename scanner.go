@@ -12,6 +12,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 const All = -1
@@ -20,18 +21,40 @@ type File struct {
 	Filename string
 	Import   string
 	Ast      *ast.File
+	FileSet  *token.FileSet // shared by all files of the scan
 }
 
-func (f *File) Imports() []string {
+// Position in the file
+func (f *File) Position(pos token.Pos) token.Position {
+	return f.FileSet.Position(pos)
+}
+
+func (f *File) Imports() ([]string, error) {
+	return importPaths(f.FileSet, f.Ast)
+}
+
+func importPaths(fset *token.FileSet, file *ast.File) ([]string, error) {
 	var imports []string
-	for _, imp := range f.Ast.Imports {
-		importName, err := strconv.Unquote(imp.Path.Value)
+	for _, imp := range file.Imports {
+		importName, err := importPath(fset, imp)
 		if err != nil {
-			panic(err)
+			return nil, err
 		}
 		imports = append(imports, importName)
 	}
-	return imports
+	return imports, nil
+}
+
+func importPath(fset *token.FileSet, imp *ast.ImportSpec) (string, error) {
+	importName, err := strconv.Unquote(imp.Path.Value)
+	if err != nil {
+		var pos token.Position
+		if fset != nil {
+			pos = fset.Position(imp.Path.Pos())
+		}
+		return "", &ImportPathError{Path: imp.Path.Value, Pos: pos, Err: err}
+	}
+	return importName, nil
 }
 
 type Import struct {
@@ -39,6 +62,8 @@ type Import struct {
 	Package   string
 	Directory string
 	Files     []*File
+	methods   map[string][]methodDecl
+	typed     *typedPackage // filled by Project.TypeCheck, shared by copies of import
 }
 
 func Scan(dir string, limit int) (Imports, error) {
@@ -66,6 +91,7 @@ func scanPackageWithLookups(importName string, path []string, packagesLimit int)
 	var imports = make(map[string]Import)
 	var packagesToScan = []string{importName}
 	var scanned int
+	var fset = token.NewFileSet()
 	for len(packagesToScan) > 0 {
 		if packagesLimit != All && scanned >= packagesLimit {
 			break
@@ -75,7 +101,7 @@ func scanPackageWithLookups(importName string, path []string, packagesLimit int)
 		if _, scanned := imports[toScan]; scanned {
 			continue
 		}
-		imp, importSet, err := scanImport(toScan, path...)
+		imp, importSet, err := scanImport(fset, toScan, path...)
 		if err != nil {
 			_, isN := err.(*ImportNotFoundErr)
 			if isN {
@@ -125,10 +151,10 @@ func lookupFolders(root string) []string {
 }
 
 // non-recursive import scan in path..
-func scanImport(importPath string, pathes ...string) (Import, []string, error) {
+func scanImport(fset *token.FileSet, importPath string, pathes ...string) (Import, []string, error) {
 	for _, path := range pathes {
 		location := filepath.Join(path, strings.Replace(importPath, "/", string(filepath.Separator), -1))
-		imp, imports, err := scanDirectory(location, importPath)
+		imp, imports, err := scanDirectory(fset, location, importPath)
 		if err != nil {
 			continue
 		}
@@ -142,7 +168,7 @@ type ImportNotFoundErr string
 
 func (in *ImportNotFoundErr) Error() string { return string(*in) }
 
-func scanDirectory(directory, assumingImportName string) (Import, []string, error) {
+func scanDirectory(fset *token.FileSet, directory, assumingImportName string) (Import, []string, error) {
 	var imp Import
 	var importSet = make(map[string]struct{})
 	imp.Import = assumingImportName
@@ -159,13 +185,13 @@ func scanDirectory(directory, assumingImportName string) (Import, []string, erro
 
 		imp.Directory = directory
 		fileName := filepath.Join(directory, fileStat.Name())
-		imports, f, err := scanFile(fileName)
-		if imp.Package == "" || strings.HasSuffix(imp.Package, "_test") {
-			imp.Package = f.Ast.Name.Name
-		}
+		imports, f, err := scanFile(fset, fileName)
 		if err != nil {
 			return imp, nil, errors.Wrapf(err, "scan file %v for import %v", fileName, assumingImportName)
 		}
+		if imp.Package == "" || strings.HasSuffix(imp.Package, "_test") {
+			imp.Package = f.Ast.Name.Name
+		}
 		for _, impPath := range imports {
 			importSet[impPath] = struct{}{}
 		}
@@ -174,6 +200,8 @@ func scanDirectory(directory, assumingImportName string) (Import, []string, erro
 	if !ok {
 		return imp, nil, errors.Errorf("no source files in %v", directory)
 	}
+	imp.methods = indexMethods(imp.Files)
+	imp.typed = &typedPackage{}
 	var allImports []string
 	for impPath := range importSet {
 		allImports = append(allImports, impPath)
@@ -181,22 +209,63 @@ func scanDirectory(directory, assumingImportName string) (Import, []string, erro
 	return imp, allImports, nil
 }
 
-func scanFile(filename string) ([]string, *File, error) {
-	tokens := token.NewFileSet()
-	file, err := parser.ParseFile(tokens, filename, nil, parser.AllErrors|parser.ParseComments)
+func scanFile(fset *token.FileSet, filename string) ([]string, *File, error) {
+	file, err := parser.ParseFile(fset, filename, nil, parser.AllErrors|parser.ParseComments)
 	if err != nil {
 		return nil, nil, err
 	}
-	var imports []string
-	for _, imp := range file.Imports {
-		importName, err := strconv.Unquote(imp.Path.Value)
+	imports, err := importPaths(fset, file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return imports, &File{Ast: file, Filename: filename, FileSet: fset}, nil
+}
+
+// package name from package clause of first non-test source file of import found in lookups folders
+func findPackageName(importPath string, lookups ...string) string {
+	for _, path := range lookups {
+		location := filepath.Join(path, filepath.FromSlash(importPath))
+		files, err := ioutil.ReadDir(location)
 		if err != nil {
-			panic(err)
+			continue
+		}
+		for _, fileStat := range files {
+			if fileStat.IsDir() || filepath.Ext(fileStat.Name()) != ".go" || strings.HasSuffix(fileStat.Name(), "_test.go") {
+				continue
+			}
+			file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(location, fileStat.Name()), nil, parser.PackageClauseOnly)
+			if err != nil || file.Name.Name == "main" {
+				continue
+			}
+			return file.Name.Name
 		}
-		imports = append(imports, importName)
 	}
+	return ""
+}
+
+// conventional package name derived from import path (the same way as goimports):
+// gopkg.in/yaml.v2 -> yaml, github.com/x/go-foo -> foo, github.com/x/foo/v2 -> foo
+func packageNameByPath(importPath string) string {
+	parts := strings.Split(importPath, "/")
+	name := parts[len(parts)-1]
+	if len(parts) > 1 && isMajorVersion(name) {
+		name = parts[len(parts)-2]
+	}
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexFunc(name, func(r rune) bool {
+		return !(r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r))
+	}); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
 
-	return imports, &File{Ast: file, Filename: filename}, nil
+func isMajorVersion(s string) bool {
+	if len(s) < 2 || s[0] != 'v' {
+		return false
+	}
+	_, err := strconv.Atoi(s[1:])
+	return err == nil
 }
 
 func findPackageByDir(fileName string, lookups ...string) (string, error) {
@@ -224,13 +293,13 @@ func findVendorDir(dir string) string {
 	} else if err == nil && st.IsDir() {
 		pth, err := filepath.Abs(vendorDir)
 		if err != nil {
-			panic(err)
+			return ""
 		}
 		return pth
 	}
 	up, err := filepath.Abs(filepath.Join(dir, ".."))
 	if err != nil {
-		panic(err)
+		return ""
 	}
 	if up == dir {
 		return ""
@@ -246,38 +315,16 @@ func findGoModuleDir(dir string) string {
 	} else if err == nil && !st.IsDir() {
 		pth, err := filepath.Abs(filepath.Join(dir, ".."))
 		if err != nil {
-			panic(err)
+			return ""
 		}
 		return pth
 	}
 	up, err := filepath.Abs(filepath.Join(dir, ".."))
 	if err != nil {
-		panic(err)
+		return ""
 	}
 	if up == dir {
 		return ""
 	}
 	return findGoModuleDir(up)
 }
-
-var builtinTypes = map[string]bool{
-	"string":      true,
-	"bool":        true,
-	"byte":        true,
-	"uint":        true,
-	"uint8":       true,
-	"uint16":      true,
-	"uint32":      true,
-	"uint64":      true,
-	"int":         true,
-	"int8":        true,
-	"int16":       true,
-	"int32":       true,
-	"int64":       true,
-	"float32":     true,
-	"float64":     true,
-	"interface{}": true,
-	"struct{}":    true, //temporary hack
-	"map":         true, //temporary hack
-	"error":       true,
-}
@@ -2,8 +2,15 @@ package symbols
 
 import (
 	"fmt"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"go/ast"
+	"go/types"
+	"io"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
 )
 
@@ -47,7 +54,7 @@ func TestVars(t *testing.T) {
 		sym := proj.Package.FindSymbol(name)
 		if assert.NotNil(t, sym, "symbol "+name) {
 			t.Log(name)
-			t.Log(sym.VarType())
+			t.Log(sym.VarType(proj))
 		}
 	}
 	fmt.Println(proj.Names())
@@ -103,3 +110,1015 @@ func TestArray(t *testing.T) {
 	assert.True(t, IsArray(rType))
 	//assert.True(t, IsIdent(ArrayItem(rType)))
 }
+
+type sampleBase struct{}
+
+func (sampleBase) Hello() string { return "hello" }
+
+func (*sampleBase) Bye() {}
+
+type SampleEmbed struct {
+	sampleBase
+	Name string
+}
+
+func (se *SampleEmbed) Own() {}
+
+type sampleShared struct {
+	Value int
+}
+
+func (sampleShared) Shared() {}
+
+type sampleLeft struct{ sampleShared }
+
+type sampleRight struct{ sampleShared }
+
+func (sampleRight) Right() {}
+
+type sampleInner struct{}
+
+func (sampleInner) B() {}
+
+func (sampleInner) C() {}
+
+type SampleOuter struct {
+	sampleInner
+	A, B int
+}
+
+type SampleDiamond struct {
+	sampleLeft
+	sampleRight
+}
+
+func TestSymbol_MethodSet(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	sym, err := proj.FindLocalSymbol("SampleEmbed")
+	assert.NoError(t, err, "find struct")
+
+	methods, err := sym.MethodSet(proj, false)
+	assert.NoError(t, err, "value method set")
+	if assert.Len(t, methods, 1) {
+		assert.Equal(t, "Hello", methods[0].Name)
+		assert.Equal(t, []string{"sampleBase"}, methods[0].Path)
+		assert.Equal(t, "sampleBase", methods[0].Function.Receiver)
+		assert.False(t, methods[0].Function.PointerReceiver)
+	}
+
+	methods, err = sym.MethodSet(proj, true)
+	assert.NoError(t, err, "pointer method set")
+	var names []string
+	for _, m := range methods {
+		names = append(names, m.Name)
+	}
+	assert.Equal(t, []string{"Bye", "Hello", "Own"}, names)
+
+	// sampleShared is reached through both embedded fields at the same depth
+	sym, err = proj.FindLocalSymbol("SampleDiamond")
+	assert.NoError(t, err, "find struct")
+	methods, err = sym.MethodSet(proj, true)
+	assert.NoError(t, err)
+	if assert.Len(t, methods, 1) {
+		assert.Equal(t, "Right", methods[0].Name)
+	}
+
+	// field B declared together with A hides promoted method B
+	sym, err = proj.FindLocalSymbol("SampleOuter")
+	assert.NoError(t, err, "find struct")
+	methods, err = sym.MethodSet(proj, false)
+	assert.NoError(t, err)
+	if assert.Len(t, methods, 1) {
+		assert.Equal(t, "C", methods[0].Name)
+	}
+}
+
+type SampleReadCloser interface {
+	io.ReadCloser
+	error
+	Greet(name string) (string, error)
+}
+
+func TestSymbol_MethodsEmbedded(t *testing.T) {
+	proj, err := ProjectByDir(".", All)
+	assert.NoError(t, err)
+	sym, err := proj.FindLocalSymbol("SampleReadCloser")
+	assert.NoError(t, err, "find interface")
+	methods, err := sym.Methods(proj)
+	assert.NoError(t, err, "methods")
+	var names, origins []string
+	for _, m := range methods {
+		names = append(names, m.Name)
+		origins = append(origins, m.Interface.Name)
+	}
+	assert.Equal(t, []string{"Greet", "Read", "Close", "Error"}, names)
+	assert.Equal(t, []string{"SampleReadCloser", "Reader", "Closer", "error"}, origins)
+	assert.Equal(t, "io", methods[1].Interface.Import.Import)
+}
+
+type sampleGreeter struct{}
+
+func (sampleGreeter) Greet(name string) (string, error) { return name, nil }
+
+type samplePtrGreeter struct{}
+
+func (*samplePtrGreeter) Greet(name string) (string, error) { return name, nil }
+
+type sampleWrongGreeter struct{}
+
+func (sampleWrongGreeter) Greet(name int) (string, error) { return "", nil }
+
+func TestProject_FindImplementations(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	iface, err := proj.FindLocalSymbol("SampleIface")
+	assert.NoError(t, err, "find interface")
+
+	wrong, err := proj.FindLocalSymbol("sampleWrongGreeter")
+	assert.NoError(t, err, "find struct")
+	ok, missing, err := wrong.Implements(proj, iface, true)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, []string{"Greet"}, missing)
+
+	impls, err := proj.FindImplementations(iface)
+	assert.NoError(t, err)
+	if assert.Len(t, impls, 2) {
+		assert.Equal(t, "sampleGreeter", impls[0].Type.Name)
+		assert.False(t, impls[0].Pointer)
+		assert.Equal(t, "samplePtrGreeter", impls[1].Type.Name)
+		assert.True(t, impls[1].Pointer)
+	}
+
+	proj, err = ProjectByDir("testdata/search", 1)
+	assert.NoError(t, err)
+	named, err := proj.FindLocalSymbol("Named")
+	assert.NoError(t, err)
+	impls, err = proj.FindImplementations(named)
+	var unchecked *UncheckedTypesError
+	if assert.True(t, errors.As(err, &unchecked)) && assert.Len(t, unchecked.Types, 1) {
+		assert.Equal(t, "buffer", unchecked.Types[0].Name)
+	}
+	assert.Len(t, impls, 2)
+}
+
+type SampleID = int64
+
+type SampleIDs []SampleID
+
+func TestSymbol_Underlying(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	id, err := proj.FindLocalSymbol("SampleID")
+	assert.NoError(t, err, "find alias")
+	assert.True(t, id.IsAlias())
+
+	target, err := id.Unalias(proj)
+	assert.NoError(t, err, "unalias")
+	assert.True(t, target.BuiltIn)
+	assert.Equal(t, "int64", target.Name)
+
+	ids, err := proj.FindLocalSymbol("SampleIDs")
+	assert.NoError(t, err, "find slice type")
+	assert.False(t, ids.IsAlias())
+	underlying, err := ids.Underlying(proj)
+	assert.NoError(t, err, "underlying")
+	assert.True(t, underlying.IsArray())
+	assert.Equal(t, "[]SampleID", underlying.Name)
+
+	item, err := proj.FindSymbol("int64", id.File)
+	assert.NoError(t, err)
+	same, err := id.Identical(proj, item)
+	assert.NoError(t, err)
+	assert.True(t, same, "alias is identical to target")
+	same, err = ids.Identical(proj, underlying)
+	assert.NoError(t, err)
+	assert.False(t, same, "defined type is not identical to underlying")
+}
+
+func TestGenerics(t *testing.T) {
+	proj, err := ProjectByDir("testdata/generics", 1)
+	assert.NoError(t, err)
+	page, err := proj.FindLocalSymbol("Page")
+	assert.NoError(t, err, "find generic struct")
+	assert.True(t, page.IsGeneric())
+	params := page.TypeParams()
+	if assert.Len(t, params, 1) {
+		assert.Equal(t, "T", params[0].Name)
+		assert.True(t, params[0].TypeParam)
+		assert.Equal(t, "any", params[0].Constraint().Name)
+	}
+
+	fields, err := page.Fields(proj)
+	assert.NoError(t, err, "fields of generic struct")
+	assert.True(t, fields[0].Type.TypeParam, "items of type parameter")
+	assert.Equal(t, "Page", fields[1].Type.Name)
+	assert.Len(t, fields[1].Type.TypeArgs, 1)
+
+	users, err := proj.FindLocalSymbol("Users")
+	assert.NoError(t, err)
+	fields, err = users.Fields(proj)
+	assert.NoError(t, err, "fields with instantiated type")
+	inst := fields[0].Type
+	assert.Equal(t, "Page", inst.Name)
+	fields, err = inst.Fields(proj)
+	assert.NoError(t, err, "fields of instantiated type")
+	assert.Equal(t, "User", fields[0].Type.Name, "type parameter substituted")
+	assert.True(t, fields[0].Type.IsStruct())
+
+	methods, err := inst.MethodSet(proj, true)
+	assert.NoError(t, err, "method set of instantiated type")
+	if assert.Len(t, methods, 1) {
+		assert.Equal(t, "First", methods[0].Name)
+		assert.Equal(t, "Page", methods[0].Function.Receiver)
+	}
+
+	fn, err := proj.FindLocalSymbol("Map")
+	assert.NoError(t, err, "find generic function")
+	assert.Len(t, fn.TypeParams(), 2)
+}
+
+func TestProject_FindPackageImport(t *testing.T) {
+	proj, err := ProjectByDir("testdata/imports", All)
+	assert.NoError(t, err, "scan")
+	file := proj.Package.Files[0]
+
+	imp, err := proj.FindPackageImport("yaml", file)
+	assert.NoError(t, err, "not scanned import")
+	assert.Equal(t, "gopkg.in/yaml.v2", imp.Import)
+	_, err = proj.FindPackageImport("ast", file)
+	assert.Error(t, err, "dot import")
+	_, err = proj.FindPackageImport("token", file)
+	assert.Error(t, err, "blank import")
+
+	sym, err := proj.FindSymbol("Ident", file)
+	assert.NoError(t, err, "dot imported symbol")
+	assert.Equal(t, "go/ast", sym.Import.Import)
+
+	assert.Equal(t, "yaml", packageNameByPath("gopkg.in/yaml.v2"))
+	assert.Equal(t, "foo", packageNameByPath("github.com/x/go-foo"))
+	assert.Equal(t, "foo", packageNameByPath("github.com/x/foo/v2"))
+}
+
+func TestSymbol_ConstValue(t *testing.T) {
+	proj, err := ProjectByDir("testdata/consts", All)
+	assert.NoError(t, err)
+	check := func(name string, value string, typeName string) {
+		sym, err := proj.FindLocalSymbol(name)
+		if !assert.NoError(t, err, "find %v", name) {
+			return
+		}
+		c, err := sym.ConstValue(proj)
+		if !assert.NoError(t, err, "evaluate %v", name) {
+			return
+		}
+		assert.Equal(t, value, c.Value.String(), name)
+		if typeName == "" {
+			assert.Nil(t, c.Type, name)
+		} else if assert.NotNil(t, c.Type, name) {
+			assert.Equal(t, typeName, c.Type.Name, name)
+		}
+	}
+	check("Debug", "0", "Level")
+	check("Info", "1", "Level")
+	check("Error", "4", "Level")
+	check("Exec", "4", "Flag")
+	check("All", "7", "Flag")
+	check("Timeout", "5000000000", "Duration")
+	check("Name", `"app-server"`, "")
+	check("Size", "10", "")
+	check("Ratio", "0.5", "")
+	check("Half", "3", "")
+	check("Mask", "255", "Flag")
+	check("Big", "40", "Level")
+	check("E", "2.71828", "float64")
+	check("Pi", "3", "float64")
+
+	proj, err = ProjectByDir("testdata/overflow", All)
+	assert.NoError(t, err)
+	check("Fits", "-128", "int8")
+	for _, name := range []string{"Small", "Unsigned", "Huge", "Wide"} {
+		sym, err := proj.FindLocalSymbol(name)
+		if assert.NoError(t, err, "find %v", name) {
+			_, err = sym.ConstValue(proj)
+			if assert.Error(t, err, name) {
+				assert.Contains(t, err.Error(), "overflows", name)
+			}
+		}
+	}
+}
+
+func TestProject_Enums(t *testing.T) {
+	proj, err := ProjectByDir("testdata/consts", All)
+	assert.NoError(t, err)
+	enums := proj.Enums()
+	if !assert.Len(t, enums, 2) {
+		return
+	}
+	assert.Equal(t, "Level", enums[0].Type.Name)
+	assert.Equal(t, "Flag", enums[1].Type.Name)
+
+	level, err := proj.FindLocalSymbol("Level")
+	assert.NoError(t, err)
+	values, err := level.EnumValues(proj)
+	assert.NoError(t, err)
+	var names []string
+	for _, v := range values {
+		names = append(names, v.Name+"="+v.Value.String())
+	}
+	assert.Equal(t, []string{"Debug=0", "Info=1", "Warn=2", "Error=4", "Big=40", "Fatal=8"}, names)
+	assert.Equal(t, "Debug messages", values[0].Doc)
+	assert.Equal(t, "information", values[1].Doc)
+	assert.Len(t, enums[0].Values, 6, "constants of alias belong to aliased type")
+
+	severity, err := proj.FindLocalSymbol("Severity")
+	assert.NoError(t, err)
+	aliased, err := severity.EnumValues(proj)
+	assert.NoError(t, err)
+	assert.Len(t, aliased, 6)
+}
+
+func TestSymbol_VarType(t *testing.T) {
+	proj, err := ProjectByDir("testdata/vars", All)
+	assert.NoError(t, err)
+	check := func(name string, typeName string) {
+		sym, err := proj.FindLocalSymbol(name)
+		if !assert.NoError(t, err, "find %v", name) {
+			return
+		}
+		tp, err := sym.VarType(proj)
+		if assert.NoError(t, err, "type of %v", name) {
+			assert.Equal(t, typeName, tp.Name, name)
+		}
+	}
+	check("declared", "int")
+	check("literal", "string")
+	check("user", "User")
+	check("ptr", "*User")
+	check("ids", "[]int64")
+	check("conv", "int64")
+	check("buf", "*Buffer")
+	check("made", "map[string]User")
+	check("created", "*User")
+	check("ref", "User")
+	check("builder", "Builder")
+	check("fromCall", "*User")
+	check("callErr", "error")
+	check("first", "int64")
+	check("name", "string")
+	check("renamed", "string")
+	check("userPtr", "*User")
+	check("deref", "User")
+	check("found", "User")
+	check("exists", "bool")
+	check("timeout", "Duration")
+	check("scaled", "int64")
+	check("double", "int")
+	check("product", "float64")
+	check("letter", "rune")
+	check("part", "float64")
+	check("reverse", "float64")
+	check("complexed", "complex128")
+
+	sym, err := proj.FindLocalSymbol("unknown")
+	assert.NoError(t, err)
+	_, err = sym.VarType(proj)
+	assert.Error(t, err)
+}
+
+func TestProject_FindSymbolUniverse(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	file := proj.Package.Files[0]
+	for name, kind := range map[string]UniverseKind{
+		"rune":       UniverseType,
+		"uintptr":    UniverseType,
+		"complex128": UniverseType,
+		"any":        UniverseType,
+		"comparable": UniverseType,
+		"len":        UniverseFunc,
+		"nil":        UniverseConst,
+		"iota":       UniverseConst,
+	} {
+		sym, err := proj.FindSymbol(name, file)
+		if assert.NoError(t, err, name) {
+			assert.True(t, sym.BuiltIn, name)
+			assert.Equal(t, kind, sym.Universe, name)
+		}
+	}
+	sym, err := proj.FindSymbol("rune", file)
+	assert.NoError(t, err)
+	assert.Equal(t, types.Int32, sym.Basic)
+	_, err = proj.FindSymbol("map", file)
+	assert.Error(t, err)
+}
+
+func TestProject_FindSymbolShadowed(t *testing.T) {
+	proj, err := ProjectByDir("testdata/shadow", All)
+	assert.NoError(t, err)
+	file := proj.Package.Files[0]
+	sym, err := proj.FindSymbol("max", file)
+	if assert.NoError(t, err) {
+		assert.False(t, sym.BuiltIn)
+		assert.True(t, sym.IsFunction())
+	}
+	sym, err = proj.FindSymbol("error", file)
+	if assert.NoError(t, err) {
+		assert.False(t, sym.BuiltIn)
+		assert.True(t, sym.IsStruct())
+	}
+	sym, err = proj.FindSymbol("min", file)
+	if assert.NoError(t, err) {
+		assert.True(t, sym.BuiltIn)
+	}
+	biggest, err := proj.FindLocalSymbol("biggest")
+	assert.NoError(t, err)
+	tp, err := biggest.VarType(proj)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "int64", tp.Name)
+	}
+	failure, err := proj.FindLocalSymbol("failure")
+	assert.NoError(t, err)
+	tp, err = failure.VarType(proj)
+	if assert.NoError(t, err) {
+		assert.False(t, tp.BuiltIn)
+	}
+	sel, err := proj.Lookup("error")
+	if assert.NoError(t, err) {
+		assert.True(t, sel.Symbol.BuiltIn)
+	}
+}
+
+func sampleFormat(prefix string, _ int, args ...SampleID) string { return prefix }
+
+func TestSignature(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	sym, err := proj.FindLocalSymbol("sampleFormat")
+	assert.NoError(t, err, "find function")
+	fn, err := sym.Function()
+	assert.NoError(t, err)
+	sig, err := fn.Signature(proj)
+	assert.NoError(t, err, "signature")
+	assert.True(t, sig.Variadic)
+	if assert.Len(t, sig.Params, 3) && assert.Len(t, sig.Results, 1) {
+		assert.Equal(t, "prefix", sig.Params[0].Var())
+		assert.Equal(t, "arg1", sig.Params[1].Var())
+		assert.Equal(t, "SampleID", sig.Params[2].Type.Name)
+		assert.True(t, sig.Params[2].Type.IsAlias())
+		assert.Equal(t, "res0", sig.Results[0].Var())
+		assert.True(t, sig.Results[0].Type.BuiltIn)
+	}
+
+	iface, err := proj.FindLocalSymbol("SampleIface")
+	assert.NoError(t, err, "find interface")
+	methods, err := iface.Methods(proj)
+	assert.NoError(t, err)
+	sig, err = methods[0].Signature(proj)
+	assert.NoError(t, err, "method signature")
+	assert.False(t, sig.Variadic)
+	assert.Len(t, sig.Params, 1)
+	assert.Len(t, sig.Results, 2)
+	assert.Equal(t, "error", sig.Results[1].Type.Name)
+}
+
+func TestProject_References(t *testing.T) {
+	proj, err := ProjectByDir("testdata/refs", All)
+	assert.NoError(t, err)
+	lines := func(sym *Symbol) map[string][]int {
+		refs, err := proj.References(sym)
+		assert.NoError(t, err)
+		var ans = make(map[string][]int)
+		for _, ref := range refs {
+			if ref.File.Import != proj.Package.Import {
+				continue
+			}
+			for _, pos := range ref.Positions {
+				name := filepath.Base(pos.Filename)
+				ans[name] = append(ans[name], pos.Line)
+			}
+		}
+		return ans
+	}
+	user, err := proj.FindLocalSymbol("User")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"a.go": {9, 10}, "b.go": {6, 8, 13, 13}}, lines(user))
+
+	name, err := proj.FindLocalSymbol("Name")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"b.go": {11}}, lines(name))
+
+	duration, err := proj.FindSymbol("tm.Duration", proj.Package.FindFile("b.go"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]int{"b.go": {7}, "c.go": {5}}, lines(duration))
+}
+
+func TestProject_TypeCheck(t *testing.T) {
+	proj, err := ProjectByDir("testdata/consts", All)
+	assert.NoError(t, err)
+	level, err := proj.FindLocalSymbol("Level")
+	assert.NoError(t, err)
+	assert.Nil(t, level.Object(), "not checked")
+	str, err := proj.FindSymbol("string", level.File)
+	assert.NoError(t, err)
+	assert.NotNil(t, str.Object(), "universe is always available")
+
+	assert.NoError(t, proj.TypeCheck())
+	assert.True(t, proj.TypeChecked())
+	obj := level.Object()
+	if assert.NotNil(t, obj) {
+		assert.Equal(t, "Level", obj.Name())
+		assert.Equal(t, "int", level.Type().Underlying().String())
+	}
+	timeout, err := proj.FindLocalSymbol("Timeout")
+	assert.NoError(t, err)
+	if assert.NotNil(t, timeout.Type()) {
+		assert.Equal(t, "time.Duration", timeout.Type().String())
+	}
+}
+
+func TestProject_TypeCheckGenerics(t *testing.T) {
+	proj, err := ProjectByDir("testdata/generics", 1)
+	assert.NoError(t, err)
+	assert.NoError(t, proj.TypeCheck())
+	users, err := proj.FindLocalSymbol("Users")
+	assert.NoError(t, err)
+	fields, err := users.Fields(proj)
+	assert.NoError(t, err)
+	page, err := fields[0].TypeExpr().Resolve(proj)
+	assert.NoError(t, err)
+	if assert.NotNil(t, page.Type()) {
+		pkg := proj.Package.Import
+		assert.Equal(t, pkg+".Page["+pkg+".User]", page.Type().String())
+	}
+}
+
+func TestSymbol_Kind(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	file := proj.Package.FindFile("scanner_test.go")
+	for name, kind := range map[string]Kind{
+		"SampleEmbed":  KindStruct,
+		"SampleIface":  KindInterface,
+		"SampleID":     KindAlias,
+		"SampleIDs":    KindType,
+		"sampleFormat": KindFunc,
+		"All":          KindConst,
+		"string":       KindBuiltin,
+	} {
+		sym, err := proj.FindSymbol(name, file)
+		if assert.NoError(t, err, name) {
+			assert.Equal(t, kind, sym.Kind(), name)
+		}
+	}
+	methods := proj.Package.MethodsOf("sampleBase")
+	if assert.NotEmpty(t, methods) {
+		assert.Equal(t, KindMethod, methods[0].Kind())
+		assert.True(t, methods[0].IsFunction(), "methods are functions too")
+	}
+
+	builtin, err := proj.FindSymbol("int", file)
+	assert.NoError(t, err)
+	assert.False(t, builtin.IsVariable())
+	assert.False(t, builtin.IsStruct())
+	var empty *Symbol
+	assert.Equal(t, KindUnknown, empty.Kind())
+	assert.False(t, empty.IsConstant())
+	assert.False(t, empty.IsType())
+}
+
+func TestProject_Lookup(t *testing.T) {
+	proj, err := ProjectByDir("testdata/imports", All)
+	assert.NoError(t, err)
+	sel, err := proj.Lookup("go/ast.Ident")
+	if assert.NoError(t, err) {
+		assert.Equal(t, KindStruct, sel.Symbol.Kind())
+		assert.Equal(t, "go/ast.Ident", sel.Symbol.ID())
+	}
+	sel, err = proj.Lookup("go/ast.Ident.Name")
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Field) {
+		assert.Equal(t, "go/ast.Ident.Name", sel.Field.ID())
+	}
+	sel, err = proj.Lookup("go/ast.Ident.String")
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Method) {
+		assert.Equal(t, "go/ast.Ident.String", sel.Method.ID())
+	}
+	config, err := proj.FindLocalSymbol("Config")
+	assert.NoError(t, err)
+	sel, err = proj.Lookup(config.ID())
+	if assert.NoError(t, err) {
+		assert.True(t, sel.Symbol.Equal(config))
+	}
+	sel, err = proj.Lookup("string")
+	if assert.NoError(t, err) {
+		assert.True(t, sel.Symbol.BuiltIn)
+	}
+	sel, err = proj.Lookup("go/ast")
+	if assert.NoError(t, err) {
+		assert.Equal(t, KindPackage, sel.Symbol.Kind())
+		assert.Equal(t, "go/ast", sel.Symbol.ID())
+		assert.Equal(t, "package", sel.Symbol.Kind().String())
+	}
+	_, err = proj.Lookup("go/ast.Missing")
+	assert.Error(t, err)
+	_, err = proj.Lookup("unknown/pkg.Type")
+	assert.Error(t, err)
+}
+
+// SampleDocumented is a sample of
+// documented type.
+//
+//symbols:mutate target=SampleDocumentedDTO exclude=Password,Token value note="hello world"
+type SampleDocumented struct {
+	//symbols:required
+	Name     string // name of user
+	Password string
+}
+
+// Sample documented constant
+const sampleDocConst = 1
+
+// Sample documented method
+func (sd *SampleDocumented) Hello() {}
+
+func TestSymbol_Doc(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	sym, err := proj.FindLocalSymbol("SampleDocumented")
+	assert.NoError(t, err)
+	assert.Equal(t, "SampleDocumented is a sample of\ndocumented type.", sym.Doc())
+
+	directive := sym.Directives().Get("symbols:mutate")
+	if assert.NotNil(t, directive) {
+		assert.Equal(t, "SampleDocumentedDTO", directive.String("target", ""))
+		assert.Equal(t, []string{"Password", "Token"}, directive.List("exclude"))
+		assert.Equal(t, "hello world", directive.String("note", ""))
+		value, err := directive.Bool("value")
+		assert.NoError(t, err)
+		assert.True(t, value)
+		assert.False(t, directive.Has("drop"))
+	}
+	fields, err := sym.Fields(proj)
+	assert.NoError(t, err)
+	assert.True(t, fields[0].Directives().Has("symbols:required"))
+	assert.Equal(t, "name of user", fields[0].Comment())
+	assert.False(t, fields[1].Directives().Has("symbols:required"))
+
+	constant, err := proj.FindLocalSymbol("sampleDocConst")
+	assert.NoError(t, err)
+	assert.Equal(t, "Sample documented constant", constant.Doc())
+
+	methods, err := sym.MethodSet(proj, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "Sample documented method", methods[0].Doc())
+
+	_, err = ParseDirective("// symbols:mutate")
+	assert.Error(t, err, "space after slashes")
+	_, err = ParseDirective(`//symbols:mutate note="unterminated`)
+	assert.Error(t, err)
+}
+
+func TestParseTags(t *testing.T) {
+	raw := `yaml:"name" json:"name,omitempty,string"  db:"-"`
+	tags := ParseTags(raw)
+	assert.NoError(t, tags.Err())
+	assert.Equal(t, []string{"yaml", "json", "db"}, tags.Keys())
+	assert.Equal(t, "name", tags.Get("json").Name)
+	assert.True(t, tags.Get("json").Has("omitempty"))
+	assert.True(t, tags.Get("json").Has("string"))
+	assert.False(t, tags.Get("yaml").Has("omitempty"))
+	assert.False(t, tags.Has("xml"))
+	assert.Equal(t, raw, tags.String(), "unchanged tags are serialized as is")
+
+	changed := tags.Set("json", "title").Delete("db").Set("xml", "x")
+	assert.Equal(t, `yaml:"name" json:"title" xml:"x"`, changed.String())
+	assert.Equal(t, raw, tags.String(), "original is not modified")
+
+	broken := ParseTags(`json:"name" yaml:name`)
+	assert.Error(t, broken.Err())
+	assert.Equal(t, []string{"json"}, broken.Keys())
+}
+
+func TestProject_FindMember(t *testing.T) {
+	proj, err := ProjectByDir("testdata/refs", All)
+	assert.NoError(t, err)
+	file := proj.Package.FindFile("b.go")
+
+	sel, err := proj.FindMember("Admin.Name", file)
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Field) {
+		assert.Equal(t, "Admin", sel.Symbol.Name)
+		assert.Equal(t, "User", sel.Field.Owner.Name)
+		assert.Equal(t, []string{"User"}, sel.Path)
+		assert.Equal(t, "string", sel.Type.Name)
+	}
+	sel, err = proj.FindMember("Admin.Timeout.String", file)
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Method) {
+		assert.Equal(t, "time.Duration.String", sel.ID())
+	}
+	sel, err = proj.FindMember("tm.Duration.Hours", file)
+	if assert.NoError(t, err) {
+		assert.NotNil(t, sel.Method)
+	}
+	sel, err = proj.FindMember("tm", file)
+	if assert.NoError(t, err) {
+		assert.Equal(t, KindPackage, sel.Symbol.Kind())
+		assert.Equal(t, "time", sel.Symbol.ID())
+	}
+	sel, err = proj.FindMember("Admin.Get", file)
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Method) {
+		assert.Equal(t, "Admin", sel.Symbol.Name)
+		assert.Equal(t, KindMethod, proj.Package.MethodsOf("Admin")[0].Kind())
+	}
+	_, err = proj.FindMember("Admin.Users.Name", file)
+	assert.Error(t, err, "slice has no members")
+	_, err = proj.FindMember("Admin.Missing", file)
+	assert.Error(t, err)
+
+	sel, err = proj.Lookup(proj.Package.Import + ".Admin.User.Name")
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Field) {
+		assert.Equal(t, proj.Package.Import+".User.Name", sel.ID())
+	}
+
+	// sampleShared.Value is promoted through both embedded fields at the same depth
+	proj, err = ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	_, err = proj.Lookup(proj.Package.Import + ".SampleDiamond.Value")
+	assert.Error(t, err)
+	sel, err = proj.Lookup(proj.Package.Import + ".SampleOuter.B")
+	if assert.NoError(t, err) {
+		assert.NotNil(t, sel.Field, "field hides promoted method")
+		assert.Nil(t, sel.Method)
+	}
+	sel, err = proj.Lookup(proj.Package.Import + ".SampleOuter.C")
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Method) {
+		assert.Equal(t, []string{"sampleInner"}, sel.Path)
+	}
+	sel, err = proj.Lookup(proj.Package.Import + ".SampleDiamond.sampleLeft.Value")
+	if assert.NoError(t, err) && assert.NotNil(t, sel.Field) {
+		assert.Equal(t, "sampleLeft", sel.Symbol.Name)
+		assert.Equal(t, "sampleShared", sel.Field.Owner.Name)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	proj, err := ProjectByDir(".", 1)
+	assert.NoError(t, err)
+	sym, err := proj.FindLocalSymbol("SampleIface")
+	assert.NoError(t, err)
+	_, err = sym.Fields(proj)
+	assert.True(t, errors.Is(err, ErrNotStruct))
+
+	file := &File{Ast: &ast.File{Imports: []*ast.ImportSpec{{Path: &ast.BasicLit{Value: "`fmt"}}}}}
+	_, err = file.Imports()
+	var pathErr *ImportPathError
+	assert.True(t, errors.As(err, &pathErr))
+	assert.Equal(t, "`fmt", pathErr.Path)
+	_, err = proj.FindPackageImport("fmt", file)
+	assert.True(t, errors.As(err, &pathErr))
+
+	broken := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(broken, "0.go"), []byte("package broken\n\nfunc {"), 0644))
+	_, err = ProjectByDir(broken, 1)
+	assert.Error(t, err)
+
+	_, err = ProjectByPackage("github.com/reddec/symbols/testdata/missing", 1)
+	assert.Error(t, err)
+}
+
+func TestProject_ExportedAPI(t *testing.T) {
+	proj, err := ProjectByDir("testdata/visibility", 1)
+	assert.NoError(t, err)
+	api, err := proj.ExportedAPI(proj.Package.Import)
+	assert.NoError(t, err)
+	var names []string
+	for _, sym := range api {
+		names = append(names, apiName(sym))
+	}
+	assert.Equal(t, []string{"Account", "Account.Check", "Admin", "NewAccount"}, names)
+
+	account, err := proj.FindLocalSymbol("Account")
+	assert.NoError(t, err)
+	fields, err := account.ExportedFields(proj)
+	assert.NoError(t, err)
+	assert.Len(t, fields, 2)
+	methods, err := account.MethodSet(proj, true)
+	assert.NoError(t, err)
+	assert.Len(t, ExportedMethods(methods), 1)
+
+	// methods of unexported type are accessible only through exported types
+	session, err := proj.FindLocalSymbol("session")
+	assert.NoError(t, err)
+	methods, err = session.MethodSet(proj, true)
+	assert.NoError(t, err)
+	assert.Len(t, methods, 1)
+	assert.Empty(t, ExportedMethods(methods))
+	admin, err := proj.FindLocalSymbol("Admin")
+	assert.NoError(t, err)
+	methods, err = admin.MethodSet(proj, false)
+	assert.NoError(t, err)
+	if assert.Len(t, ExportedMethods(methods), 1) {
+		assert.Equal(t, "Valid", methods[0].Name)
+	}
+
+	_, err = proj.ExportedAPI("example.com/missing")
+	assert.True(t, errors.Is(err, ErrImportNotFound))
+}
+
+func TestSymbol_Zero(t *testing.T) {
+	proj, err := ProjectByDir("testdata/zero", 1)
+	assert.NoError(t, err)
+	comparable := map[string]bool{"Level": true, "Tags": false, "Point": true, "Grid": false, "Profile": false, "Box": false}
+	for name, expected := range comparable {
+		sym, err := proj.FindLocalSymbol(name)
+		assert.NoError(t, err)
+		ok, err := sym.Comparable(proj)
+		assert.NoError(t, err, name)
+		assert.Equal(t, expected, ok, name)
+	}
+
+	profile, err := proj.FindLocalSymbol("Profile")
+	assert.NoError(t, err)
+	zero, err := profile.Zero(proj)
+	assert.NoError(t, err)
+	assert.Equal(t, ZeroFields, zero.Check)
+	assert.Equal(t, "Profile{}", zero.Value)
+	checks := make(map[string]ZeroCheck)
+	for _, f := range zero.Fields {
+		checks[f.Name] = f.Zero.Check
+	}
+	assert.Equal(t, map[string]ZeroCheck{
+		"Name": ZeroCompare, "Level": ZeroCompare, "Tags": ZeroLen, "Point": ZeroCompare, "Meta": ZeroLen,
+		"Handler": ZeroNil, "Owner": ZeroNil, "Err": ZeroNil, "Grid": ZeroElements,
+	}, checks)
+	assert.Equal(t, ZeroLen, zero.Fields[len(zero.Fields)-1].Zero.Elem.Check)
+
+	box, err := proj.FindLocalSymbol("Box")
+	assert.NoError(t, err)
+	fields, err := box.Fields(proj)
+	assert.NoError(t, err)
+	zero, err = fields[0].TypeExpr().Zero(proj)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "*new(K)", zero.Value)
+	}
+	_, err = fields[1].TypeExpr().Zero(proj)
+	assert.Error(t, err, "any is not comparable")
+}
+
+func TestProject_Dependencies(t *testing.T) {
+	proj, err := ProjectByDir("testdata/refs", All)
+	assert.NoError(t, err)
+	admin, err := proj.FindLocalSymbol("Admin")
+	assert.NoError(t, err)
+	deps, err := proj.Dependencies(admin, true)
+	assert.NoError(t, err)
+	if assert.Len(t, deps.Imports, 2) {
+		assert.Equal(t, proj.Package.Import, deps.Imports[0].Import)
+		assert.Equal(t, "User", deps.Imports[0].Symbols[0].Name)
+		assert.Equal(t, "time", deps.Imports[1].Import)
+		assert.True(t, deps.Imports[1].Std)
+		assert.Equal(t, "Duration", deps.Imports[1].Symbols[0].Name)
+	}
+	assert.Empty(t, deps.Cycles)
+
+	proj, err = ProjectByDir("testdata/refs", 1)
+	assert.NoError(t, err)
+	admin, err = proj.FindLocalSymbol("Admin")
+	assert.NoError(t, err)
+	deps, err = proj.Dependencies(admin, true)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"time.Duration"}, deps.Unresolved)
+
+	proj, err = ProjectByDir("testdata/generics", 1)
+	assert.NoError(t, err)
+	users, err := proj.FindLocalSymbol("Users")
+	assert.NoError(t, err)
+	deps, err = proj.Dependencies(users, true)
+	assert.NoError(t, err)
+	if assert.Len(t, deps.Imports, 1) {
+		var names []string
+		for _, sym := range deps.Imports[0].Symbols {
+			names = append(names, sym.Name)
+		}
+		assert.Equal(t, []string{"Page", "User"}, names)
+	}
+	if assert.Len(t, deps.Cycles, 1) {
+		assert.Equal(t, "Page", deps.Cycles[0][0].Name)
+	}
+}
+
+func TestProject_Search(t *testing.T) {
+	proj, err := ProjectByDir("testdata/search", 1)
+	assert.NoError(t, err)
+	names := func(query Query) []string {
+		found, err := proj.Search(query)
+		assert.NoError(t, err)
+		var ans []string
+		for _, sym := range found {
+			ans = append(ans, apiName(sym))
+		}
+		return ans
+	}
+	assert.Equal(t, []string{"User.String", "account.String"}, names(Query{Name: "*.String"}))
+	assert.Equal(t, []string{"User"}, names(Query{Kinds: []Kind{KindStruct}, Exported: true}))
+	assert.Equal(t, []string{"account"}, names(Query{Tag: "db"}))
+	assert.Equal(t, []string{"User"}, names(Query{Directive: "symbols:mutate"}))
+	assert.Equal(t, []string{"Version"}, names(Query{NameRegexp: regexp.MustCompile("^V"), Import: proj.Package.Import}))
+	assert.Empty(t, names(Query{Import: "example.com/*"}))
+
+	var lines []int
+	inits, err := proj.Search(Query{Name: "init"})
+	assert.NoError(t, err)
+	for _, sym := range inits {
+		lines = append(lines, sym.Position().Line)
+	}
+	assert.Equal(t, []int{9, 10}, lines)
+
+	named, err := proj.FindLocalSymbol("Named")
+	assert.NoError(t, err)
+	found, err := proj.Search(Query{Implements: named})
+	var unchecked *UncheckedTypesError
+	if assert.True(t, errors.As(err, &unchecked)) && assert.Len(t, unchecked.Types, 1) {
+		assert.Equal(t, "buffer", unchecked.Types[0].Name)
+	}
+	if assert.Len(t, found, 2) {
+		assert.Equal(t, "User", found[0].Name)
+		assert.Equal(t, "account", found[1].Name)
+	}
+	assert.Equal(t, []string{"Named"}, names(Query{Implements: named, Kinds: []Kind{KindInterface}}))
+}
+
+func TestProject_CallGraph(t *testing.T) {
+	proj, err := ProjectByDir("testdata/calls", 1)
+	assert.NoError(t, err)
+	cg := proj.CallGraph()
+	pkg := proj.Package.Import + "."
+	calls := func(id string) []string {
+		var ans []string
+		for _, node := range cg.Node(pkg + id).Calls {
+			ans = append(ans, strings.TrimPrefix(node.Symbol.ID(), pkg))
+		}
+		return ans
+	}
+	assert.Equal(t, []string{"normalize"}, calls("Repo.Load"))
+	assert.Equal(t, []string{"Item.Name", "Repo.Load"}, calls("Service.Get"))
+	assert.Equal(t, []string{"NewService", "Run", "Service.Get"}, calls("Run"))
+	assert.Equal(t, []string{"Item.Name", "Repo.Load"}, calls("Preload"))
+	assert.Empty(t, calls("normalize"))
+	assert.Len(t, cg.Node(pkg+"Service.Get").Callers, 1)
+
+	var inits []*CallNode
+	for _, node := range cg.Functions {
+		if node.Symbol.Name == "init" {
+			inits = append(inits, node)
+		}
+	}
+	if assert.Len(t, inits, 2, "every init function is a separate node") {
+		assert.Equal(t, pkg+"normalize", inits[0].Calls[0].Symbol.ID())
+		assert.Equal(t, pkg+"Preload", inits[1].Calls[0].Symbol.ID())
+		assert.True(t, inits[1] == cg.Node(inits[1].Key()))
+		assert.Len(t, cg.Node(pkg+"normalize").Callers, 2)
+	}
+
+	buf := &strings.Builder{}
+	assert.NoError(t, cg.WriteDOT(buf))
+	assert.Contains(t, buf.String(), fmt.Sprintf("%q -> %q;", pkg+"Run", pkg+"NewService"))
+}
+
+func TestProject_FieldUsage(t *testing.T) {
+	proj, err := ProjectByDir("testdata/usage", 1)
+	assert.NoError(t, err)
+	order, err := proj.FindLocalSymbol("Order")
+	assert.NoError(t, err)
+	usages, err := proj.FieldUsage(order)
+	assert.NoError(t, err)
+	byName := make(map[string]*FieldUsage)
+	var names []string
+	for _, usage := range usages {
+		byName[usage.Field.Name] = usage
+		names = append(names, usage.Field.Name)
+	}
+	assert.Equal(t, []string{"Base", "ID", "Total", "Note", "Unused", "Hidden", "Tags"}, names)
+	if assert.Len(t, byName, 7) {
+		assert.Len(t, byName["ID"].Reads, 1)
+		assert.Len(t, byName["ID"].Literals, 2)
+		assert.Len(t, byName["Total"].Reads, 1)
+		assert.Len(t, byName["Total"].Writes, 1)
+		assert.True(t, byName["Note"].WriteOnly())
+		assert.True(t, byName["Tags"].WriteOnly())
+		assert.True(t, byName["Unused"].Unused())
+		assert.True(t, byName["Hidden"].Unused())
+		// promoted field Created is selected through Base
+		assert.Len(t, byName["Base"].Reads, 1)
+		assert.False(t, byName["Base"].Unused())
+	}
+
+	base, err := proj.FindLocalSymbol("Base")
+	assert.NoError(t, err)
+	usages, err = proj.FieldUsage(base)
+	assert.NoError(t, err)
+	if assert.Len(t, usages, 1) {
+		assert.True(t, usages[0].WriteOnly())
+		assert.Equal(t, 24, usages[0].Writes[0].Line)
+	}
+}
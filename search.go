@@ -0,0 +1,188 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/token"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Filter of declarations. Empty fields are not checked, all other conditions should be satisfied.
+type Query struct {
+	Name         string         // glob pattern (path.Match) of name, methods are matched as Type.Method
+	NameRegexp   *regexp.Regexp // regular expression of name, methods are matched as Type.Method
+	Import       string         // glob pattern (path.Match) of import path
+	ImportRegexp *regexp.Regexp // regular expression of import path
+	Kinds        []Kind         // one of kinds
+	Exported     bool           // only exported declarations
+	Tag          string         // struct has field with the tag key
+	Directive    string         // declaration has the directive (ex: symbols:mutate)
+	Implements   *Symbol        // type or pointer to the type satisfies the interface, interfaces only if KindInterface is in Kinds
+}
+
+// Find package level declarations and methods in all scanned imports. Results are sorted by import path,
+// name (Type.Method for methods) and position. Types which method sets can't be resolved for Implements
+// (ex: not scanned embedded types) are reported by UncheckedTypesError returned together with found symbols.
+func (prj *Project) Search(query Query) ([]*Symbol, error) {
+	if query.Implements != nil && !query.Implements.IsInterface() && !query.Implements.BuiltIn {
+		return nil, errors.Wrap(ErrNotInterface, query.Implements.Name)
+	}
+	var (
+		ans       []*Symbol
+		unchecked = &UncheckedTypesError{}
+	)
+	for i := range prj.Imports {
+		imp := &prj.Imports[i]
+		ok, err := query.matchImport(imp.Import)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		for _, sym := range imp.declarations() {
+			ok, err := query.match(sym)
+			if err != nil {
+				return nil, errors.Wrapf(err, "check %v", sym.ID())
+			}
+			if !ok {
+				continue
+			}
+			if query.Implements != nil {
+				ok, err = query.implements(prj, sym)
+				if err != nil {
+					unchecked.Types = append(unchecked.Types, sym)
+					unchecked.Errors = append(unchecked.Errors, err)
+					continue
+				}
+				if !ok {
+					continue
+				}
+			}
+			ans = append(ans, sym)
+		}
+	}
+	sort.SliceStable(ans, func(i, j int) bool {
+		a, b := ans[i], ans[j]
+		if a.Import.Import != b.Import.Import {
+			return a.Import.Import < b.Import.Import
+		}
+		if apiName(a) != apiName(b) {
+			return apiName(a) < apiName(b)
+		}
+		return positionLess(a.Position(), b.Position())
+	})
+	if len(unchecked.Types) > 0 {
+		return ans, unchecked
+	}
+	return ans, nil
+}
+
+func positionLess(a, b token.Position) bool {
+	if a.Filename != b.Filename {
+		return a.Filename < b.Filename
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+func (q *Query) matchImport(importPath string) (bool, error) {
+	if q.Import != "" {
+		ok, err := path.Match(q.Import, importPath)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return q.ImportRegexp == nil || q.ImportRegexp.MatchString(importPath), nil
+}
+
+func (q *Query) match(sym *Symbol) (bool, error) {
+	name := apiName(sym)
+	if q.Name != "" {
+		ok, err := path.Match(q.Name, name)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	if q.NameRegexp != nil && !q.NameRegexp.MatchString(name) {
+		return false, nil
+	}
+	if len(q.Kinds) > 0 && !q.hasKind(sym.Kind()) {
+		return false, nil
+	}
+	if q.Exported && !sym.Exported() {
+		return false, nil
+	}
+	if q.Directive != "" && !sym.Directives().Has(q.Directive) {
+		return false, nil
+	}
+	if q.Tag != "" {
+		st, err := sym.structType()
+		if err != nil || !hasTag(st, q.Tag) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (q *Query) implements(prj *Project, sym *Symbol) (bool, error) {
+	if !sym.IsType() || sym.IsAlias() || sym.IsInterface() && !q.hasKind(KindInterface) {
+		return false, nil
+	}
+	ok, _, err := sym.Implements(prj, q.Implements, true)
+	return ok, err
+}
+
+func (q *Query) hasKind(kind Kind) bool {
+	for _, k := range q.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTag(st *ast.StructType, key string) bool {
+	for _, f := range st.Fields.List {
+		if f.Tag == nil {
+			continue
+		}
+		raw, _ := strconv.Unquote(f.Tag.Value)
+		if ParseTags(raw).Has(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// package level declarations and methods in order of files and declarations
+func (imp *Import) declarations() []*Symbol {
+	var ans []*Symbol
+	for _, f := range imp.Files {
+		for _, decl := range f.Ast.Decls {
+			switch v := decl.(type) {
+			case *ast.FuncDecl:
+				ans = append(ans, &Symbol{Import: imp, File: f, Node: v, ParentNode: f.Ast, Name: v.Name.Name})
+			case *ast.GenDecl:
+				for _, spec := range v.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						ans = append(ans, &Symbol{Import: imp, File: f, Node: s, ParentNode: v, Name: s.Name.Name})
+					case *ast.ValueSpec:
+						for _, name := range s.Names {
+							if name.Name != "_" {
+								ans = append(ans, &Symbol{Import: imp, File: f, Node: name, ParentNode: s, Name: name.Name})
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return ans
+}
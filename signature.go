@@ -0,0 +1,91 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"strconv"
+)
+
+type Signature struct {
+	Params   []*Param
+	Results  []*Param
+	Variadic bool // last parameter declared as ...T
+}
+
+type Param struct {
+	Name    string  // empty for unnamed parameters
+	Type    *Symbol // resolved type: declaration for type names, type expression otherwise. Element type for variadic parameter
+	RawType ast.Expr
+	Index   int  // position in parameters or results
+	Result  bool // is function result
+}
+
+// Name of parameter or generated name (argN, resN) for unnamed and blank parameters
+func (p *Param) Var() string {
+	if p.Name != "" && p.Name != "_" {
+		return p.Name
+	}
+	if p.Result {
+		return "res" + strconv.Itoa(p.Index)
+	}
+	return "arg" + strconv.Itoa(p.Index)
+}
+
+// Is the parameter variadic (...T)
+func (p *Param) IsVariadic() bool {
+	_, ok := p.RawType.(*ast.Ellipsis)
+	return ok
+}
+
+// Parameters and results of method with resolved types
+func (m *Method) Signature(resolver Resolver) (*Signature, error) {
+	return newSignature(resolver, m.signature(), m.RawCall)
+}
+
+// Parameters and results of function with resolved types. Receiver is not included.
+func (fn *Function) Signature(resolver Resolver) (*Signature, error) {
+	scope := fn.scope
+	if scope == nil {
+		scope = &Symbol{File: fn.File, Node: fn.Raw, Name: fn.Name}
+	}
+	return newSignature(resolver, scope, fn.Raw.Type)
+}
+
+// signature of function type declared in scope of symbol
+func newSignature(resolver Resolver, scope *Symbol, fn *ast.FuncType) (*Signature, error) {
+	params, err := newParams(resolver, scope, fn.Params, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "params")
+	}
+	results, err := newParams(resolver, scope, fn.Results, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "results")
+	}
+	sig := &Signature{Params: params, Results: results}
+	if len(params) > 0 {
+		sig.Variadic = params[len(params)-1].IsVariadic()
+	}
+	return sig, nil
+}
+
+func newParams(resolver Resolver, scope *Symbol, list *ast.FieldList, result bool) ([]*Param, error) {
+	var ans []*Param
+	for _, f := range flatFields(list) {
+		typeExpr := f.field.Type
+		if ellipsis, ok := typeExpr.(*ast.Ellipsis); ok {
+			typeExpr = ellipsis.Elt
+		}
+		tp, err := scope.TypeExpr(typeExpr).Resolve(resolver)
+		if err != nil {
+			return nil, errors.Wrapf(err, "resolve type of parameter %v", len(ans))
+		}
+		ans = append(ans, &Param{
+			Name:    f.name,
+			Type:    tp,
+			RawType: f.field.Type,
+			Index:   len(ans),
+			Result:  result,
+		})
+	}
+	return ans, nil
+}
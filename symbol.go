@@ -2,9 +2,10 @@ package symbols
 
 import (
 	"fmt"
-	"github.com/davecgh/go-spew/spew"
 	"github.com/pkg/errors"
 	"go/ast"
+	"go/token"
+	"go/types"
 	"reflect"
 	"strconv"
 	"strings"
@@ -17,6 +18,11 @@ type Symbol struct {
 	ParentNode ast.Node
 	Name       string
 	BuiltIn    bool
+	Universe   UniverseKind    // kind of predeclared identifier for built-in symbols
+	Basic      types.BasicKind // basic kind of predeclared type or constant
+	TypeParam  bool            // type parameter of generic type or function
+	TypeArgs   []*Symbol       // type arguments of instantiated generic type or function
+	scope      *Symbol         // generic declaration which type parameters are visible in type expression
 }
 
 func (sym *Symbol) WithNode(node ast.Node) *Symbol {
@@ -26,7 +32,7 @@ func (sym *Symbol) WithNode(node ast.Node) *Symbol {
 }
 
 func (sym *Symbol) Is(importPath string, typeName string) bool {
-	if sym.BuiltIn {
+	if sym == nil || sym.BuiltIn || sym.Import == nil {
 		return false
 	}
 	if sym.Name != typeName {
@@ -36,20 +42,37 @@ func (sym *Symbol) Is(importPath string, typeName string) bool {
 }
 
 func (sym *Symbol) Equal(b *Symbol) bool {
+	if sym == nil || b == nil {
+		return sym == b
+	}
 	if sym.Name != b.Name {
 		return false
 	}
+	if sym.TypeParam || b.TypeParam {
+		return sym.TypeParam == b.TypeParam && sym.ParentNode == b.ParentNode
+	}
 	if sym.BuiltIn != b.BuiltIn {
 		return false
 	}
-	if !sym.BuiltIn && (sym.Import.Import != b.Import.Import) {
+	if !sym.BuiltIn && (sym.importPath() != b.importPath()) {
 		return false
 	}
 	return true
 }
 
+// Position of declaration or expression, zero for built-in symbols
+func (sym *Symbol) Position() token.Position {
+	if sym == nil || sym.File == nil || sym.File.FileSet == nil || sym.Node == nil {
+		return token.Position{}
+	}
+	return sym.File.Position(sym.Node.Pos())
+}
+
 func (sym *Symbol) String() string {
-	if sym.BuiltIn {
+	if sym == nil {
+		return "<nil>"
+	}
+	if sym.BuiltIn || sym.Import == nil {
 		return sym.Name
 	}
 	var val interface{}
@@ -62,137 +85,105 @@ func (sym *Symbol) String() string {
 	return fmt.Sprint(sym.Import.Package, "{", sym.Import.Import, "}", sym.Name, "=", val)
 }
 
-func (sym *Symbol) ArrayItem(resolver Resolver) *Symbol {
-	v := sym.Node.(*ast.ArrayType)
-	sm, err := resolver.FindSymbol(realTypeQN(v.Elt), sym.File)
-	if err != nil {
-		panic(err)
+// Base type of array or slice element, nil for other symbols
+func (sym *Symbol) ArrayItem(resolver Resolver) (*Symbol, error) {
+	v, ok := sym.node().(*ast.ArrayType)
+	if !ok {
+		return nil, nil
 	}
-	return sm
+	return sym.TypeExpr(v.Elt).baseType(resolver)
 }
 
+// Is declaration of function or method (Kind is KindFunc or KindMethod)
 func (sym *Symbol) IsFunction() bool {
-	_, ok := sym.Node.(*ast.FuncDecl)
-	return ok
+	return IsFunction(sym.node())
+}
+
+func (sym *Symbol) IsMethod() bool {
+	return IsMethod(sym.node())
 }
 
 func (sym *Symbol) IsArray() bool {
-	_, ok := sym.Node.(*ast.ArrayType)
-	return ok
+	return IsArray(sym.node())
 }
 
 func (sym *Symbol) IsPointer() bool {
-	_, ok := sym.Node.(*ast.StarExpr)
-	return ok
+	return IsPointer(sym.node())
 }
 
 func (sym *Symbol) IsStruct() bool {
-	if !sym.IsType() {
-		return false
-	}
-	_, ok := (sym.Node.(*ast.TypeSpec)).Type.(*ast.StructType)
-	return ok
+	return IsStruct(sym.node())
 }
 
+// Is symbol of struct type literal (struct{...})
 func (sym *Symbol) IsStructDefinition() bool {
-	if !sym.IsType() {
-		return false
-	}
-	_, ok := sym.Node.(*ast.StructType)
-	return ok
+	return IsStructDefinition(sym.node())
 }
 
 func (sym *Symbol) IsMap() bool {
-	_, ok := sym.Node.(*ast.MapType)
-	return ok
+	return IsMap(sym.node())
 }
 
 func (sym *Symbol) IsInterface() bool {
-	if !sym.IsType() {
-		return false
-	}
-	_, ok := (sym.Node.(*ast.TypeSpec)).Type.(*ast.InterfaceType)
-	return ok
+	return IsInterface(sym.node())
 }
 
 func (sym *Symbol) IsVariable() bool {
-	v, ok := sym.Node.(*ast.Ident)
-	if !ok {
-		return false
-	}
-	if v.Obj.Kind != ast.Var {
-		return false
-	}
-	return true
+	return IsVariable(sym.node())
 }
 
 func (sym *Symbol) IsConstant() bool {
-	v, ok := sym.Node.(*ast.Ident)
-	if !ok {
-		return false
-	}
-	if v.Obj.Kind != ast.Con {
-		return false
-	}
-	return true
+	return IsConstant(sym.node())
 }
 
 func (sym *Symbol) IsCall() bool {
-	_, ok := sym.Node.(*ast.CallExpr)
-	return ok
+	return IsCall(sym.node())
 }
 
 func (sym *Symbol) IsLiteral() bool {
-	_, ok := sym.Node.(*ast.BasicLit)
-	return ok
+	return IsLiteral(sym.node())
 }
 
 func (sym *Symbol) Literal() (string, error) {
-	v, ok := sym.Node.(*ast.BasicLit)
-	if !ok {
-		return "", errors.Errorf("is not a literal")
-	}
-	return v.Value, nil
+	return Literal(sym.node())
+}
+
+// Type of variable: declared type or type inferred from value (literals, composite literals, conversions,
+// calls of functions and methods with known result types, fields, indexing and dereferencing).
+// Type names are resolved to declarations.
+func (sym *Symbol) VarType(resolver Resolver) (*Symbol, error) {
+	return sym.varType(resolver, 0)
 }
 
-func (sym *Symbol) VarType() (*Symbol, error) {
+func (sym *Symbol) varType(resolver Resolver, depth int) (*Symbol, error) {
 	v, ok := sym.Node.(*ast.Ident)
-	if !ok {
+	if !ok || v.Obj == nil {
 		return nil, errors.New("is not a var")
 	}
-	switch v.Obj.Kind {
-	case ast.Var:
-		switch o := v.Obj.Decl.(type) {
-		case *ast.ValueSpec:
-			spew.Dump(o)
-			return &Symbol{
-				Node:   o.Values[0],
-				Name:   o.Names[0].Name,
-				File:   sym.File,
-				Import: sym.Import,
-			}, nil
-		default:
-			return nil, errors.Errorf("unknown var type %v for symbol %v", unref(reflect.ValueOf(o).Type()).Name(), sym.Name)
+	if v.Obj.Kind != ast.Var {
+		return nil, errors.Errorf("unknown var kind %v", v.Obj.Kind)
+	}
+	if depth > maxResolveDepth {
+		return nil, errors.Errorf("too deep variables references for %v", sym.Name)
+	}
+	spec, ok := v.Obj.Decl.(*ast.ValueSpec)
+	if !ok {
+		return nil, errors.Errorf("unknown var type %v for symbol %v", unref(reflect.ValueOf(v.Obj.Decl).Type()).Name(), sym.Name)
+	}
+	if spec.Type != nil {
+		return sym.TypeExpr(spec.Type).Resolve(resolver)
+	}
+	index := -1
+	for i, name := range spec.Names {
+		if name == v || (index == -1 && name.Name == v.Name) {
+			index = i
 		}
 	}
-	return nil, errors.Errorf("unknown var kind %v", v.Obj.Kind)
-}
-
-func symbolOf(node ast.Node) *Symbol {
-	//builtinTypes[qualifiedName]
-	switch t := node.(type) {
-	case *ast.StarExpr:
-		return &Symbol{Node: t}
-	case *ast.ArrayType:
-		return &Symbol{Node: t}
-	case *ast.SelectorExpr:
-	case *ast.StructType:
-	case *ast.MapType:
-	case *ast.Ident:
-	default:
-		panic("unknown symbol type")
+	tp, err := sym.inferAssigned(resolver, spec.Values, index, len(spec.Names), depth)
+	if err != nil {
+		return nil, errors.Wrapf(err, "variable %v", sym.Name)
 	}
-	return nil
+	return tp, nil
 }
 
 type InfoNode struct {
@@ -207,17 +198,23 @@ func unref(v reflect.Type) reflect.Type {
 }
 
 func (sym *Symbol) IsType() bool {
-	_, ok := sym.Node.(*ast.TypeSpec)
-	return ok
+	return IsType(sym.node())
 }
 
 type Field struct {
-	Name    string
-	Type    *Symbol
-	RawType ast.Expr
-	Raw     *ast.Field
-	Parent  *ast.TypeSpec
-	Tags    map[string]string
+	Name     string
+	Type     *Symbol
+	RawType  ast.Expr
+	Raw      *ast.Field
+	Parent   *ast.TypeSpec
+	Tags     Tags
+	Embedded bool    // field declared without name, Name is a type name
+	Owner    *Symbol // struct where the field is declared
+}
+
+// Complete type expression of the field as symbol
+func (f *Field) TypeExpr() *Symbol {
+	return f.Owner.TypeExpr(f.RawType)
 }
 
 func (f *Field) Comment() string {
@@ -235,15 +232,21 @@ func (f *Field) Comment() string {
 }
 
 func (sym *Symbol) Fields(resolver Resolver) ([]*Field, error) {
-	tps := sym.Node.(*ast.TypeSpec)
-	st, ok := (sym.Node.(*ast.TypeSpec)).Type.(*ast.StructType)
-	if !ok {
-		return nil, errors.New("is not a struct")
+	if sym.IsAlias() {
+		target, err := sym.Unalias(resolver)
+		if err != nil {
+			return nil, err
+		}
+		return target.Fields(resolver)
+	}
+	st, err := sym.structType()
+	if err != nil {
+		return nil, err
 	}
 	var ans []*Field
 	for _, p := range st.Fields.List {
 		if len(p.Names) == 1 {
-			field, err := wrapField(p, tps, resolver, sym.File)
+			field, err := wrapField(p, sym, resolver)
 			if err != nil {
 				return nil, err
 			}
@@ -255,9 +258,9 @@ func (sym *Symbol) Fields(resolver Resolver) ([]*Field, error) {
 }
 
 func (sym *Symbol) FieldsNames() ([]string, error) {
-	st, ok := (sym.Node.(*ast.TypeSpec)).Type.(*ast.StructType)
-	if !ok {
-		return nil, errors.New("is not a struct")
+	st, err := sym.structType()
+	if err != nil {
+		return nil, err
 	}
 	var ans []string
 	for _, p := range st.Fields.List {
@@ -268,54 +271,181 @@ func (sym *Symbol) FieldsNames() ([]string, error) {
 	return ans, nil
 }
 
-func wrapField(p *ast.Field, parent *ast.TypeSpec, resolver Resolver, file *File) (*Field, error) {
-	sm, err := resolver.FindSymbol(realTypeQN(p.Type), file)
+// Embedded (anonymous) fields of struct
+func (sym *Symbol) EmbeddedFields(resolver Resolver) ([]*Field, error) {
+	st, err := sym.structType()
 	if err != nil {
-		return nil, errors.Wrapf(err, "get real type of %v", p.Names[0])
+		return nil, err
 	}
-	var rawTags string
+	var ans []*Field
+	for _, p := range st.Fields.List {
+		if len(p.Names) == 0 {
+			field, err := wrapField(p, sym, resolver)
+			if err != nil {
+				return nil, err
+			}
+			ans = append(ans, field)
+		}
+	}
+	return ans, nil
+}
+
+func (sym *Symbol) structType() (*ast.StructType, error) {
+	if tps, ok := sym.node().(*ast.TypeSpec); ok {
+		if st, ok := tps.Type.(*ast.StructType); ok {
+			return st, nil
+		}
+	}
+	return nil, errors.Wrap(ErrNotStruct, sym.String())
+}
+
+func wrapField(p *ast.Field, owner *Symbol, resolver Resolver) (*Field, error) {
+	var name string
+	if len(p.Names) > 0 {
+		name = p.Names[0].Name
+	} else {
+		name = embeddedName(p)
+	}
+	sm, err := owner.TypeExpr(p.Type).baseType(resolver)
+	if err != nil {
+		return nil, errors.Wrapf(err, "get real type of %v", name)
+	}
+	var tags Tags
 	if p.Tag != nil {
-		rawTags, _ = strconv.Unquote(p.Tag.Value)
+		rawTags, _ := strconv.Unquote(p.Tag.Value)
+		tags = ParseTags(rawTags)
+		tags.literal = p.Tag.Value
 	}
 	return &Field{
-		Name:    p.Names[0].Name,
-		Type:    sm,
-		RawType: p.Type,
-		Raw:     p,
-		Tags:    parseTags(rawTags),
-		Parent:  parent,
+		Name:     name,
+		Type:     sm,
+		RawType:  p.Type,
+		Raw:      p,
+		Tags:     tags,
+		Parent:   owner.Node.(*ast.TypeSpec),
+		Embedded: len(p.Names) == 0,
+		Owner:    owner,
 	}, nil
 }
 
 type Method struct {
-	Name    string
-	Raw     *ast.Field
-	RawCall *ast.FuncType
+	Name      string
+	Raw       *ast.Field    // interface method declaration, nil for concrete methods
+	RawCall   *ast.FuncType // method type
+	Function  *Function     // concrete method declaration, nil for interface methods
+	Interface *Symbol       // interface where the method is declared, nil for concrete methods
+	Path      []string      // names of embedded fields through which the method is promoted
+	File      *File         // file where the method is declared
+	scope     *Symbol       // declaration of interface or concrete method
 }
 
-func (sym *Symbol) Methods(resolver Resolver) ([]*Method, error) {
-	ifs, ok := (sym.Node.(*ast.TypeSpec)).Type.(*ast.InterfaceType)
-	if !ok {
-		return nil, errors.New("is not a interface")
+// method type as symbol of type expression
+func (m *Method) signature() *Symbol {
+	if m.scope != nil {
+		return m.scope.TypeExpr(m.RawCall)
 	}
+	return typeExprSymbol(m.RawCall, m.File, nil)
+}
+
+// Complete method set of interface including methods from embedded interfaces (resolved recursively
+// across packages). Own methods go first in declaration order, followed by methods of embedded interfaces.
+func (sym *Symbol) Methods(resolver Resolver) ([]*Method, error) {
 	var ans []*Method
+	err := sym.collectMethods(resolver, make(map[string]bool), make(map[string]bool), &ans)
+	return ans, err
+}
+
+func (sym *Symbol) collectMethods(resolver Resolver, visited map[string]bool, names map[string]bool, ans *[]*Method) error {
+	if sym.BuiltIn && sym.Name == "error" {
+		if !names["Error"] {
+			names["Error"] = true
+			*ans = append(*ans, errorMethod(sym))
+		}
+		return nil
+	}
+	if !sym.IsInterface() {
+		return errors.Wrap(ErrNotInterface, sym.Name)
+	}
+	key := sym.Import.Import + "." + sym.Name
+	if visited[key] {
+		return nil
+	}
+	visited[key] = true
+	ifs := (sym.Node.(*ast.TypeSpec)).Type.(*ast.InterfaceType)
+	var embedded []ast.Expr
 	for _, method := range ifs.Methods.List {
-		if len(method.Names) == 1 {
-			name := method.Names[0].Name
-			fn := method.Type.(*ast.FuncType)
-			ans = append(ans, &Method{
-				Name:    name,
-				Raw:     method,
-				RawCall: fn,
+		if len(method.Names) == 0 {
+			embedded = append(embedded, method.Type)
+			continue
+		}
+		fn, ok := method.Type.(*ast.FuncType)
+		if !ok {
+			continue
+		}
+		for _, ident := range method.Names {
+			if names[ident.Name] {
+				// the same method from other embedded interface
+				continue
+			}
+			names[ident.Name] = true
+			*ans = append(*ans, &Method{
+				Name:      ident.Name,
+				Raw:       method,
+				RawCall:   fn,
+				Interface: sym,
+				File:      sym.File,
+				scope:     sym,
 			})
 		}
 	}
-	return ans, nil
+	for _, expr := range embedded {
+		switch expr.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		default:
+			// type set elements (unions, approximations) have no methods
+			continue
+		}
+		iface, err := sym.TypeExpr(expr).Unalias(resolver)
+		if err != nil {
+			return errors.Wrapf(err, "resolve embedded interface in %v", sym.Name)
+		}
+		if !iface.IsInterface() && !(iface.BuiltIn && iface.Name == "error") {
+			// type set element
+			continue
+		}
+		err = iface.collectMethods(resolver, visited, names, ans)
+		if err != nil {
+			return errors.Wrapf(err, "methods of embedded %v", iface.Name)
+		}
+	}
+	return nil
+}
+
+// Error() string method of built-in error interface
+func errorMethod(errorSym *Symbol) *Method {
+	return &Method{
+		Name: "Error",
+		RawCall: &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: ast.NewIdent("string")}}},
+		},
+		Interface: errorSym,
+		File:      errorSym.File,
+	}
 }
 
 type Function struct {
-	Name string
-	Raw  *ast.FuncDecl
+	Name            string
+	Raw             *ast.FuncDecl
+	File            *File
+	Receiver        string // base type name of receiver, empty for plain functions
+	PointerReceiver bool
+	scope           *Symbol // declaration of function
+}
+
+// Is function declared with receiver
+func (fn *Function) IsMethod() bool {
+	return fn.Receiver != ""
 }
 
 func (sym *Symbol) Function() (*Function, error) {
@@ -323,9 +453,14 @@ func (sym *Symbol) Function() (*Function, error) {
 	if !ok {
 		return nil, errors.New("is not a method")
 	}
+	receiver, pointer := receiverType(ifs)
 	return &Function{
-		Name: ifs.Name.Name,
-		Raw:  ifs,
+		Name:            ifs.Name.Name,
+		Raw:             ifs,
+		File:            sym.File,
+		Receiver:        receiver,
+		PointerReceiver: pointer,
+		scope:           sym,
 	}, nil
 }
 
@@ -339,66 +474,18 @@ func realTypeQN(t ast.Node) string {
 	if v, ok := t.(*ast.SelectorExpr); ok {
 		return realTypeQN(v.X) + "." + v.Sel.Name
 	}
-	if _, ok := t.(*ast.StructType); ok {
-		// embedded struct
-		// todo: do as normal
-		return "struct{}"
+	if v, ok := t.(*ast.IndexExpr); ok {
+		// generic instantiation
+		return realTypeQN(v.X)
 	}
-	if _, ok := t.(*ast.MapType); ok {
-		// todo: respect map
-		return "map"
+	if v, ok := t.(*ast.IndexListExpr); ok {
+		return realTypeQN(v.X)
 	}
-	v := t.(*ast.Ident)
-	return v.Name
-}
-
-// see: func (tag StructTag) Lookup(key string)
-func parseTags(tag string) map[string]string {
-	ans := make(map[string]string)
-	for tag != "" {
-		// Skip leading space.
-		i := 0
-		for i < len(tag) && tag[i] == ' ' {
-			i++
-		}
-		tag = tag[i:]
-		if tag == "" {
-			break
-		}
-
-		// Scan to colon. A space, a quote or a control character is a syntax error.
-		// Strictly speaking, control chars include the range [0x7f, 0x9f], not just
-		// [0x00, 0x1f], but in practice, we ignore the multi-byte control characters
-		// as it is simpler to inspect the tag's bytes than the tag's runes.
-		i = 0
-		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
-			i++
-		}
-		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
-			break
-		}
-		name := string(tag[:i])
-		tag = tag[i+1:]
-
-		// Scan quoted string to find value.
-		i = 1
-		for i < len(tag) && tag[i] != '"' {
-			if tag[i] == '\\' {
-				i++
-			}
-			i++
-		}
-		if i >= len(tag) {
-			break
-		}
-		qvalue := string(tag[:i+1])
-		tag = tag[i+1:]
-
-		value, err := strconv.Unquote(qvalue)
-		if err != nil {
-			break
-		}
-		ans[name] = value
+	if v, ok := t.(*ast.Ident); ok {
+		return v.Name
+	}
+	if expr, ok := t.(ast.Expr); ok {
+		return types.ExprString(expr)
 	}
-	return ans
+	return ""
 }
@@ -0,0 +1,206 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// Struct tag item: key:"name,option1,option2"
+type Tag struct {
+	Key     string
+	Value   string   // unquoted value
+	Name    string   // value before first comma
+	Options []string // comma separated values after name
+}
+
+// Is option defined in tag (ex: omitempty)
+func (t Tag) Has(option string) bool {
+	for _, opt := range t.Options {
+		if opt == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Struct tags in original order
+type Tags struct {
+	raw     string
+	literal string // original Go literal of tag
+	items   []Tag
+	err     error
+	changed bool
+}
+
+// Parse struct tag (without quotes) the same way as reflect.StructTag. Parsing stops on first malformed
+// item, the error is available by Err.
+func ParseTags(tag string) Tags {
+	tags := Tags{raw: tag}
+	for tag != "" {
+		// Skip leading space.
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		// Scan to colon. A space, a quote or a control character is a syntax error.
+		// Strictly speaking, control chars include the range [0x7f, 0x9f], not just
+		// [0x00, 0x1f], but in practice, we ignore the multi-byte control characters
+		// as it is simpler to inspect the tag's bytes than the tag's runes.
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			tags.err = errors.Errorf("bad syntax for struct tag pair at %q", tag)
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		// Scan quoted string to find value.
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			tags.err = errors.Errorf("bad syntax for struct tag value of %v", name)
+			break
+		}
+		qvalue := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(qvalue)
+		if err != nil {
+			tags.err = errors.Wrapf(err, "bad syntax for struct tag value of %v", name)
+			break
+		}
+		tags.items = append(tags.items, newTag(name, value))
+	}
+	return tags
+}
+
+func newTag(key, value string) Tag {
+	parts := strings.Split(value, ",")
+	return Tag{Key: key, Value: value, Name: parts[0], Options: parts[1:]}
+}
+
+// Tag by key, zero tag if not defined
+func (tags Tags) Get(key string) Tag {
+	tag, _ := tags.Lookup(key)
+	return tag
+}
+
+// Tag by key
+func (tags Tags) Lookup(key string) (Tag, bool) {
+	for _, tag := range tags.items {
+		if tag.Key == key {
+			return tag, true
+		}
+	}
+	return Tag{}, false
+}
+
+// Is tag with key defined
+func (tags Tags) Has(key string) bool {
+	_, ok := tags.Lookup(key)
+	return ok
+}
+
+// Tags in original order
+func (tags Tags) List() []Tag {
+	return append([]Tag(nil), tags.items...)
+}
+
+// Keys of tags in original order
+func (tags Tags) Keys() []string {
+	var ans []string
+	for _, tag := range tags.items {
+		ans = append(ans, tag.Key)
+	}
+	return ans
+}
+
+func (tags Tags) Len() int {
+	return len(tags.items)
+}
+
+// Syntax error of malformed tag
+func (tags Tags) Err() error {
+	return tags.err
+}
+
+// Copy of tags with new value for the key. New keys are added to the end.
+func (tags Tags) Set(key, value string) Tags {
+	items := make([]Tag, 0, len(tags.items)+1)
+	var replaced bool
+	for _, tag := range tags.items {
+		if tag.Key == key {
+			tag = newTag(key, value)
+			replaced = true
+		}
+		items = append(items, tag)
+	}
+	if !replaced {
+		items = append(items, newTag(key, value))
+	}
+	return Tags{items: items, changed: true}
+}
+
+// Copy of tags without the key
+func (tags Tags) Delete(key string) Tags {
+	if !tags.Has(key) {
+		return tags
+	}
+	var items []Tag
+	for _, tag := range tags.items {
+		if tag.Key != key {
+			items = append(items, tag)
+		}
+	}
+	return Tags{items: items, changed: true}
+}
+
+// Values of tags by keys
+func (tags Tags) Map() map[string]string {
+	ans := make(map[string]string, len(tags.items))
+	for _, tag := range tags.items {
+		ans[tag.Key] = tag.Value
+	}
+	return ans
+}
+
+// Struct tag (without quotes). Not modified tags are returned exactly as parsed.
+func (tags Tags) String() string {
+	if !tags.changed {
+		return tags.raw
+	}
+	var items []string
+	for _, tag := range tags.items {
+		items = append(items, tag.Key+":"+strconv.Quote(tag.Value))
+	}
+	return strings.Join(items, " ")
+}
+
+// Struct tag as Go literal: raw string if possible, otherwise quoted. Empty for empty tags.
+func (tags Tags) Literal() string {
+	if !tags.changed && tags.literal != "" {
+		return tags.literal
+	}
+	s := tags.String()
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "`"):
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,68 @@
+package calls
+
+import "strings"
+
+type Item struct {
+	ID string
+}
+
+func (i Item) Name() string {
+	return strings.ToUpper(i.ID)
+}
+
+type Repo struct{}
+
+func (r *Repo) Load(id string) (*Item, error) {
+	return &Item{ID: normalize(id)}, nil
+}
+
+func normalize(id string) string {
+	return strings.TrimSpace(id)
+}
+
+type Service struct {
+	repo *Repo
+}
+
+func NewService() *Service {
+	return &Service{repo: &Repo{}}
+}
+
+func (s *Service) Get(id string) string {
+	item, err := s.repo.Load(id)
+	if err != nil {
+		return ""
+	}
+	return item.Name()
+}
+
+func Run(ids []string) {
+	svc := NewService()
+	for _, id := range ids {
+		svc.Get(id)
+	}
+	(*Service).Get(svc, "root")
+	if len(ids) > 0 {
+		Run(ids[1:])
+	}
+}
+
+func Preload(items map[string]*Item) {
+	repo := new(Repo)
+	for id, item := range items {
+		if loaded, err := repo.Load(id); err == nil {
+			item = loaded
+		}
+		item.Name()
+	}
+}
+
+var registry = make(map[string]*Item)
+
+func init() {
+	registry["root"] = &Item{ID: normalize("root")}
+}
+
+func init() {
+	Preload(registry)
+}
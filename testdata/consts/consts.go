@@ -0,0 +1,41 @@
+package consts
+
+import "time"
+
+type Level int
+
+// Levels of logging
+const (
+	// Debug messages
+	Debug Level = iota
+	Info        // information
+	Warn
+	_
+	Error
+)
+
+type Flag uint8
+
+const (
+	Read Flag = 1 << iota
+	Write
+	Exec
+	All = Read | Write | Exec
+)
+
+const (
+	Timeout = 5 * time.Second
+	Prefix  = "app" + "-"
+	Name    = Prefix + "server"
+	Size    = len(Name)
+	Ratio   = 1 / 2.0
+	Half    = 7 / 2
+	Mask    = ^Flag(0)
+	Big     = Level(Exec) * 10
+)
+
+const Pi, E float64 = 3, 2.71828
+
+type Severity = Level
+
+const Fatal Severity = 8
@@ -0,0 +1,40 @@
+package generics
+
+type Number interface {
+	~int | ~int64 | ~float64
+}
+
+type Page[T any] struct {
+	Items []T
+	Next  *Page[T]
+}
+
+type PageDTO[T any] struct {
+	Items []T
+	Total int
+}
+
+type Pair[K comparable, V Number] struct {
+	Key   K
+	Value V
+}
+
+type User struct {
+	Name string
+}
+
+type Users struct {
+	Page Page[User]
+}
+
+func (p *Page[T]) First() T {
+	return p.Items[0]
+}
+
+func Map[T, R any](items []T, fn func(T) R) []R {
+	var ans []R
+	for _, item := range items {
+		ans = append(ans, fn(item))
+	}
+	return ans
+}
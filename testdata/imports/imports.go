@@ -0,0 +1,13 @@
+package imports
+
+import (
+	. "go/ast"
+	_ "go/token"
+
+	"gopkg.in/yaml.v2"
+)
+
+type Config struct {
+	Name  *Ident
+	Value yaml.MapSlice
+}
@@ -0,0 +1,13 @@
+package overflow
+
+// invalid on purpose: values don't fit into declared types
+
+type Flag uint8
+
+const (
+	Small    int8    = 200
+	Unsigned uint    = -1
+	Huge     float32 = 1e40
+	Wide             = Flag(1) << 8
+	Fits     int8    = -128
+)
@@ -0,0 +1,11 @@
+package refs
+
+type User struct {
+	Name string
+}
+
+const Name = "name"
+
+func NewUser() *User {
+	return &User{Name: "root"}
+}
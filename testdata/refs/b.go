@@ -0,0 +1,17 @@
+package refs
+
+import tm "time"
+
+type Admin struct {
+	User
+	Timeout tm.Duration
+	Users   []*User
+}
+
+var keys = map[string]int{Name: 1}
+
+func (a *Admin) Get(u User) (User, error) {
+	var User = 1
+	_ = User
+	return u, nil
+}
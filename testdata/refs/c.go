@@ -0,0 +1,5 @@
+package refs
+
+import . "time"
+
+var timeout Duration
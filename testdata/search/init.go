@@ -0,0 +1,10 @@
+package search
+
+var registered []string
+
+func register(name string) { registered = append(registered, name) }
+
+// initialization order matters
+
+func init() { register("first") }
+func init() { register("second") }
@@ -0,0 +1,34 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Named interface {
+	String() string
+}
+
+//symbols:mutate target=UserDTO
+type User struct {
+	Name string `json:"name"`
+}
+
+func (u User) String() string {
+	return u.Name
+}
+
+type account struct {
+	ID int `db:"id"`
+}
+
+func (a *account) String() string {
+	return strconv.Itoa(a.ID)
+}
+
+// method set depends on not scanned package
+type buffer struct {
+	strings.Builder
+}
+
+const Version = "1"
@@ -0,0 +1,18 @@
+package shadow
+
+type error struct {
+	Code int
+}
+
+func max(a, b int) int64 {
+	if a > b {
+		return int64(a)
+	}
+	return int64(b)
+}
+
+var (
+	biggest  = max(1, 2)
+	smallest = min(1, 2)
+	failure  error
+)
@@ -0,0 +1,34 @@
+package usage
+
+type Base struct {
+	Created int64
+}
+
+type Order struct {
+	Base
+	ID             string
+	Total          int
+	Note           string
+	Unused, Hidden bool
+	Tags           map[string]string
+}
+
+var defaults = []Order{{ID: "default"}}
+
+func NewOrder(id string) *Order {
+	return &Order{ID: id, Note: "new"}
+}
+
+func (o *Order) Add(amount int) {
+	o.Total += amount
+	o.Created = 1
+	o.Tags["added"] = "true"
+}
+
+func Describe(orders []*Order) string {
+	var ans string
+	for _, o := range orders {
+		ans += o.ID
+	}
+	return ans
+}
@@ -0,0 +1,53 @@
+package vars
+
+import (
+	"bytes"
+	"strings"
+	"time"
+)
+
+type User struct {
+	Name string
+}
+
+const (
+	retries = 3
+	ratio   = 1.5
+)
+
+func NewUser() (*User, error) { return &User{}, nil }
+
+func (u User) Rename(name string) string { return name }
+
+var (
+	declared int
+	literal  = "text"
+	user     = User{Name: "root"}
+	ptr      = &User{}
+	ids      = []int64{1, 2}
+	conv     = int64(10)
+	buf      = bytes.NewBufferString("")
+	made     = make(map[string]User)
+	created  = new(User)
+	ref      = user
+	builder  strings.Builder
+
+	fromCall, callErr = NewUser()
+
+	first         = ids[0]
+	name          = user.Name
+	renamed       = user.Rename("admin")
+	userPtr       = &user
+	deref         = *ptr
+	found, exists = made["root"]
+
+	timeout   = retries * time.Second
+	scaled    = (retries + 1) * conv
+	double    = retries * 2
+	product   = 1.5 * 2
+	letter    = 'a' + 1
+	part      = ratio * 2
+	reverse   = 2 * ratio
+	complexed = 2i * retries
+	unknown   = nil
+)
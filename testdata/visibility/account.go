@@ -0,0 +1,31 @@
+package visibility
+
+type Account struct {
+	ID       int
+	Name     string
+	password string
+}
+
+func (a *Account) Check(password string) bool {
+	return a.password == password
+}
+
+func (a *Account) reset() {
+	a.password = ""
+}
+
+type session struct {
+	Token string
+}
+
+func (s *session) Valid() bool {
+	return s.Token != ""
+}
+
+type Admin struct {
+	*session
+}
+
+func NewAccount(name string) *Account {
+	return &Account{Name: name}
+}
@@ -0,0 +1,28 @@
+package zero
+
+type Level int
+
+type Tags []string
+
+type Point struct {
+	X, Y int
+}
+
+type Grid [2][]int
+
+type Profile struct {
+	Name    string
+	Level   Level
+	Tags    Tags
+	Point   Point
+	Meta    map[string]string
+	Handler func()
+	Owner   *Profile
+	Err     error
+	Grid    Grid
+}
+
+type Box[K comparable, V any] struct {
+	Key   K
+	Value V
+}
@@ -0,0 +1,196 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+)
+
+type typedPackage struct {
+	Package *types.Package
+	Info    *types.Info
+}
+
+// Type-check project package and (recursively) its imports by go/types. Scanned imports are checked from
+// the same AST, not scanned imports are checked from sources found by go/build. After the check
+// Symbol.Object and Symbol.Type are available. Errors in dependencies are tolerated, returned the first
+// error of the project package.
+func (prj *Project) TypeCheck() error {
+	if len(prj.Package.Files) == 0 {
+		return errors.New("no files")
+	}
+	fset := prj.Package.Files[0].FileSet
+	checker := &typeChecker{
+		project:  prj,
+		fset:     fset,
+		fallback: importer.ForCompiler(fset, "source", nil),
+		packages: make(map[string]*types.Package),
+	}
+	_, err := checker.check(prj.Package.Import)
+	return err
+}
+
+// Is project type-checked
+func (prj *Project) TypeChecked() bool {
+	return prj.Package.typed != nil && prj.Package.typed.Info != nil
+}
+
+type typeChecker struct {
+	project  *Project
+	fset     *token.FileSet
+	fallback types.Importer
+	packages map[string]*types.Package
+}
+
+func (tc *typeChecker) Import(importPath string) (*types.Package, error) {
+	if importPath == "unsafe" {
+		return types.Unsafe, nil
+	}
+	pkg, err := tc.check(importPath)
+	if pkg != nil {
+		return pkg, nil
+	}
+	return nil, err
+}
+
+func (tc *typeChecker) check(importPath string) (*types.Package, error) {
+	if pkg, ok := tc.packages[importPath]; ok {
+		if pkg == nil {
+			return nil, errors.Errorf("import cycle via %v", importPath)
+		}
+		return pkg, nil
+	}
+	imp := tc.project.Imports.ByImport(importPath)
+	if imp == nil || imp.typed == nil {
+		pkg, err := tc.fallback.Import(importPath)
+		tc.packages[importPath] = pkg
+		return pkg, err
+	}
+	if imp.typed.Package != nil {
+		tc.packages[importPath] = imp.typed.Package
+		return imp.typed.Package, nil
+	}
+	tc.packages[importPath] = nil
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Instances:  make(map[*ast.Ident]types.Instance),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Implicits:  make(map[ast.Node]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		Scopes:     make(map[ast.Node]*types.Scope),
+	}
+	var firstErr error
+	conf := types.Config{
+		Importer:    tc,
+		FakeImportC: true,
+		Error: func(err error) {
+			if firstErr == nil {
+				firstErr = err
+			}
+		},
+	}
+	pkg, _ := conf.Check(importPath, tc.fset, buildFiles(imp), info)
+	imp.typed.Package = pkg
+	imp.typed.Info = info
+	tc.packages[importPath] = pkg
+	if firstErr != nil {
+		return pkg, errors.Wrapf(firstErr, "type-check %v", importPath)
+	}
+	return pkg, nil
+}
+
+// non-test files of package matched by build constraints of current platform
+func buildFiles(imp *Import) []*ast.File {
+	var ans []*ast.File
+	for _, f := range imp.Files {
+		name := filepath.Base(f.Filename)
+		if strings.HasSuffix(name, "_test.go") || f.Ast.Name.Name != imp.Package {
+			continue
+		}
+		if ok, err := build.Default.MatchFile(filepath.Dir(f.Filename), name); err != nil || !ok {
+			continue
+		}
+		ans = append(ans, f.Ast)
+	}
+	return ans
+}
+
+// Object of declaration (or of type name in type expression) from type-checked project.
+// Returns nil if project is not type-checked or symbol has no object (ex: type literal).
+// Predeclared identifiers always have objects of universe scope, even without type checking.
+func (sym *Symbol) Object() types.Object {
+	if sym.BuiltIn {
+		return types.Universe.Lookup(sym.Name)
+	}
+	info := sym.typesInfo()
+	if info == nil {
+		return nil
+	}
+	var ident *ast.Ident
+	switch v := sym.Node.(type) {
+	case *ast.TypeSpec:
+		ident = v.Name
+	case *ast.FuncDecl:
+		ident = v.Name
+	case *ast.Ident:
+		ident = v
+	case *ast.SelectorExpr:
+		ident = v.Sel
+	default:
+		return nil
+	}
+	if obj := info.Defs[ident]; obj != nil {
+		return obj
+	}
+	return info.Uses[ident]
+}
+
+// Type of declaration or type expression from type-checked project. Instantiated generic symbols are
+// instantiated by types of arguments. Returns nil if project is not type-checked or type is unknown.
+func (sym *Symbol) Type() types.Type {
+	if len(sym.TypeArgs) > 0 {
+		generic := sym.WithNode(sym.Node)
+		generic.TypeArgs = nil
+		base := generic.Type()
+		if base == nil {
+			return nil
+		}
+		var args []types.Type
+		for _, arg := range sym.TypeArgs {
+			argType := arg.Type()
+			if argType == nil {
+				return nil
+			}
+			args = append(args, argType)
+		}
+		inst, err := types.Instantiate(nil, base, args, false)
+		if err != nil {
+			return nil
+		}
+		return inst
+	}
+	if obj := sym.Object(); obj != nil {
+		return obj.Type()
+	}
+	info := sym.typesInfo()
+	if info == nil {
+		return nil
+	}
+	if expr, ok := sym.Node.(ast.Expr); ok {
+		return info.Types[expr].Type
+	}
+	return nil
+}
+
+func (sym *Symbol) typesInfo() *types.Info {
+	if sym.Import == nil || sym.Import.typed == nil {
+		return nil
+	}
+	return sym.Import.typed.Info
+}
@@ -0,0 +1,91 @@
+package symbols
+
+import (
+	"go/types"
+)
+
+// Kind of predeclared identifier
+type UniverseKind int
+
+const (
+	UniverseType  UniverseKind = iota + 1 // bool, int, string, error, any, comparable and etc.
+	UniverseFunc                          // append, len, make, new and etc.
+	UniverseConst                         // true, false, iota and nil
+)
+
+func (uk UniverseKind) String() string {
+	switch uk {
+	case UniverseType:
+		return "type"
+	case UniverseFunc:
+		return "func"
+	case UniverseConst:
+		return "const"
+	}
+	return ""
+}
+
+type universeObject struct {
+	Kind  UniverseKind
+	Basic types.BasicKind // for basic types and constants
+}
+
+// predeclared identifiers of universe scope
+var universe = map[string]universeObject{
+	// types
+	"bool":       {Kind: UniverseType, Basic: types.Bool},
+	"byte":       {Kind: UniverseType, Basic: types.Byte},
+	"complex64":  {Kind: UniverseType, Basic: types.Complex64},
+	"complex128": {Kind: UniverseType, Basic: types.Complex128},
+	"float32":    {Kind: UniverseType, Basic: types.Float32},
+	"float64":    {Kind: UniverseType, Basic: types.Float64},
+	"int":        {Kind: UniverseType, Basic: types.Int},
+	"int8":       {Kind: UniverseType, Basic: types.Int8},
+	"int16":      {Kind: UniverseType, Basic: types.Int16},
+	"int32":      {Kind: UniverseType, Basic: types.Int32},
+	"int64":      {Kind: UniverseType, Basic: types.Int64},
+	"rune":       {Kind: UniverseType, Basic: types.Rune},
+	"string":     {Kind: UniverseType, Basic: types.String},
+	"uint":       {Kind: UniverseType, Basic: types.Uint},
+	"uint8":      {Kind: UniverseType, Basic: types.Uint8},
+	"uint16":     {Kind: UniverseType, Basic: types.Uint16},
+	"uint32":     {Kind: UniverseType, Basic: types.Uint32},
+	"uint64":     {Kind: UniverseType, Basic: types.Uint64},
+	"uintptr":    {Kind: UniverseType, Basic: types.Uintptr},
+	"error":      {Kind: UniverseType},
+	"any":        {Kind: UniverseType},
+	"comparable": {Kind: UniverseType},
+	// constants
+	"true":  {Kind: UniverseConst, Basic: types.UntypedBool},
+	"false": {Kind: UniverseConst, Basic: types.UntypedBool},
+	"iota":  {Kind: UniverseConst, Basic: types.UntypedInt},
+	"nil":   {Kind: UniverseConst, Basic: types.UntypedNil},
+	// functions
+	"append":  {Kind: UniverseFunc},
+	"cap":     {Kind: UniverseFunc},
+	"clear":   {Kind: UniverseFunc},
+	"close":   {Kind: UniverseFunc},
+	"complex": {Kind: UniverseFunc},
+	"copy":    {Kind: UniverseFunc},
+	"delete":  {Kind: UniverseFunc},
+	"imag":    {Kind: UniverseFunc},
+	"len":     {Kind: UniverseFunc},
+	"make":    {Kind: UniverseFunc},
+	"max":     {Kind: UniverseFunc},
+	"min":     {Kind: UniverseFunc},
+	"new":     {Kind: UniverseFunc},
+	"panic":   {Kind: UniverseFunc},
+	"print":   {Kind: UniverseFunc},
+	"println": {Kind: UniverseFunc},
+	"real":    {Kind: UniverseFunc},
+	"recover": {Kind: UniverseFunc},
+}
+
+// symbol of predeclared identifier or nil
+func universeSymbol(name string, file *File) *Symbol {
+	obj, ok := universe[name]
+	if !ok {
+		return nil
+	}
+	return &Symbol{Name: name, File: file, BuiltIn: true, Universe: obj.Kind, Basic: obj.Basic}
+}
@@ -0,0 +1,218 @@
+package symbols
+
+import (
+	"go/ast"
+	"go/token"
+)
+
+// Usages of struct field in scanned imports
+type FieldUsage struct {
+	Field    *Field
+	Reads    []token.Position // selectors in expressions (including compound assignments and increments)
+	Writes   []token.Position // selectors in left side of assignments, increments and index assignments
+	Literals []token.Position // keyed or positional values in composite literals of the struct
+}
+
+// Field is not read, written or initialized anywhere
+func (fu *FieldUsage) Unused() bool {
+	return len(fu.Reads) == 0 && len(fu.Writes) == 0 && len(fu.Literals) == 0
+}
+
+// Field is written or initialized by literals, but never read
+func (fu *FieldUsage) WriteOnly() bool {
+	return len(fu.Reads) == 0 && (len(fu.Writes) > 0 || len(fu.Literals) > 0)
+}
+
+// Find reads, writes and composite literal initializations of fields (including embedded) of struct in function
+// bodies and initializers of package level variables. Selectors are resolved by types of receivers, parameters,
+// variables, fields and call results where they can be determined, promoted fields are accounted to the declaring
+// struct and selection of promoted field or method is a read of embedded fields of the struct on the path.
+// Usages through reflection or unresolvable expressions are not found. Result is in order of declaration.
+func (prj *Project) FieldUsage(sym *Symbol) ([]*FieldUsage, error) {
+	sym, err := sym.Unalias(prj)
+	if err != nil {
+		return nil, err
+	}
+	st, err := sym.structType()
+	if err != nil {
+		return nil, err
+	}
+	uf := &usageFinder{
+		project: prj,
+		owner:   sym.ID(),
+		byName:  make(map[string]*FieldUsage),
+		methods: make(map[string][]*Method),
+	}
+	var ans []*FieldUsage
+	for _, f := range flatFields(st.Fields) {
+		field, err := wrapField(f.field, sym, prj)
+		if err != nil {
+			return nil, err
+		}
+		if f.name != "" {
+			field.Name = f.name
+		}
+		uf.order = append(uf.order, field.Name)
+		uf.embeds = uf.embeds || field.Embedded
+		if field.Name == "_" {
+			continue
+		}
+		usage := &FieldUsage{Field: field}
+		uf.byName[field.Name] = usage
+		ans = append(ans, usage)
+	}
+	for i := range prj.Imports {
+		for _, decl := range prj.Imports[i].declarations() {
+			uf.scan(decl)
+		}
+	}
+	return ans, nil
+}
+
+type usageFinder struct {
+	project *Project
+	owner   string   // id of struct
+	order   []string // names of fields in order of declaration for positional literals
+	embeds  bool     // struct has embedded fields
+	byName  map[string]*FieldUsage
+	methods map[string][]*Method
+}
+
+func (uf *usageFinder) scan(decl *Symbol) {
+	var root ast.Node
+	switch v := decl.Node.(type) {
+	case *ast.FuncDecl:
+		if v.Body == nil {
+			return
+		}
+		root = v.Body
+	case *ast.Ident:
+		spec, ok := decl.ParentNode.(*ast.ValueSpec)
+		if !ok || !decl.IsVariable() || spec.Names[0] != v {
+			// values of specification are scanned once
+			return
+		}
+		root = spec
+	default:
+		return
+	}
+	cr := &callResolver{project: uf.project, scope: decl, methods: uf.methods}
+	writes := make(map[ast.Expr]bool)
+	reads := make(map[ast.Expr]bool)
+	ast.Inspect(root, func(node ast.Node) bool {
+		switch v := node.(type) {
+		case *ast.AssignStmt:
+			for _, lhs := range v.Lhs {
+				lhs = unparen(lhs)
+				if index, ok := lhs.(*ast.IndexExpr); ok {
+					lhs = unparen(index.X)
+				}
+				writes[lhs] = true
+				reads[lhs] = v.Tok != token.ASSIGN && v.Tok != token.DEFINE
+			}
+		case *ast.IncDecStmt:
+			writes[unparen(v.X)] = true
+			reads[unparen(v.X)] = true
+		case *ast.SelectorExpr:
+			uf.selector(cr, decl.File, v, writes[v], reads[v])
+		case *ast.CompositeLit:
+			uf.literal(cr, decl.File, v)
+		}
+		return true
+	})
+}
+
+// account selected field of analyzed struct and embedded fields of the struct through which member is promoted
+func (uf *usageFinder) selector(cr *callResolver, file *File, sel *ast.SelectorExpr, write, read bool) {
+	if uf.byName[sel.Sel.Name] == nil && !uf.embeds {
+		return
+	}
+	owner := cr.exprType(sel.X)
+	if owner == nil {
+		return
+	}
+	named, err := namedType(uf.project, owner)
+	if err != nil {
+		return
+	}
+	member, err := selectMember(uf.project, named, sel.Sel.Name)
+	if err != nil {
+		return
+	}
+	pos := file.Position(sel.Sel.Pos())
+	current := named
+	for _, name := range member.Path {
+		if usage := uf.byName[name]; usage != nil && current.ID() == uf.owner {
+			usage.Reads = append(usage.Reads, pos)
+		}
+		field, _, err := findField(uf.project, current, name)
+		if err != nil || field == nil {
+			break
+		}
+		if current, err = namedType(uf.project, field.TypeExpr()); err != nil {
+			break
+		}
+	}
+	usage := uf.byName[sel.Sel.Name]
+	if usage == nil || member.Field == nil || member.Field.Owner.ID() != uf.owner {
+		return
+	}
+	if write {
+		usage.Writes = append(usage.Writes, pos)
+	}
+	if !write || read {
+		usage.Reads = append(usage.Reads, pos)
+	}
+}
+
+func (uf *usageFinder) literal(cr *callResolver, file *File, lit *ast.CompositeLit) {
+	if lit.Type == nil {
+		return
+	}
+	tp := cr.scope.TypeExpr(lit.Type)
+	if named, err := namedType(uf.project, tp); err == nil && named.ID() == uf.owner {
+		uf.structLiteral(file, lit)
+		return
+	}
+	// elements with elided types in literals of slices, arrays and maps
+	underlying, err := tp.Underlying(uf.project)
+	if err != nil {
+		return
+	}
+	var elem ast.Expr
+	switch v := underlying.Node.(type) {
+	case *ast.ArrayType:
+		elem = v.Elt
+	case *ast.MapType:
+		elem = v.Value
+	default:
+		return
+	}
+	if named, err := namedType(uf.project, underlying.TypeExpr(elem)); err != nil || named.ID() != uf.owner {
+		return
+	}
+	for _, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			elt = kv.Value
+		}
+		if item, ok := elt.(*ast.CompositeLit); ok && item.Type == nil {
+			uf.structLiteral(file, item)
+		}
+	}
+}
+
+func (uf *usageFinder) structLiteral(file *File, lit *ast.CompositeLit) {
+	for i, elt := range lit.Elts {
+		name, pos := "", elt.Pos()
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				name, pos = key.Name, key.Pos()
+			}
+		} else if i < len(uf.order) {
+			name = uf.order[i]
+		}
+		if usage := uf.byName[name]; usage != nil {
+			usage.Literals = append(usage.Literals, file.Position(pos))
+		}
+	}
+}
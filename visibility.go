@@ -0,0 +1,110 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"sort"
+)
+
+// Is symbol accessible from other packages: declarations with upper-case names and predeclared identifiers.
+// Methods are exported only if receiver type is exported too.
+func (sym *Symbol) Exported() bool {
+	if sym == nil {
+		return false
+	}
+	if sym.BuiltIn {
+		return true
+	}
+	if sym.TypeParam || !ast.IsExported(sym.Name) {
+		return false
+	}
+	if fn, ok := sym.Node.(*ast.FuncDecl); ok && fn.Recv != nil {
+		recv, _ := receiverType(fn)
+		return ast.IsExported(recv)
+	}
+	return true
+}
+
+// Is field accessible from other packages. Embedded fields are named by type name.
+func (f *Field) Exported() bool {
+	return ast.IsExported(f.Name)
+}
+
+// Is method accessible from other packages. Declared methods are checked as declarations (see Symbol.Exported),
+// promoted and interface methods are accessible through the enclosing type, so only their names are checked.
+func (m *Method) Exported() bool {
+	if m.Function != nil && m.scope != nil && len(m.Path) == 0 {
+		return m.scope.Exported()
+	}
+	return ast.IsExported(m.Name)
+}
+
+// Exported fields of struct
+func (sym *Symbol) ExportedFields(resolver Resolver) ([]*Field, error) {
+	fields, err := sym.Fields(resolver)
+	if err != nil {
+		return nil, err
+	}
+	return ExportedFields(fields), nil
+}
+
+// Filter exported fields
+func ExportedFields(fields []*Field) []*Field {
+	var ans []*Field
+	for _, f := range fields {
+		if f.Exported() {
+			ans = append(ans, f)
+		}
+	}
+	return ans
+}
+
+// Filter exported methods
+func ExportedMethods(methods []*Method) []*Method {
+	var ans []*Method
+	for _, m := range methods {
+		if m.Exported() {
+			ans = append(ans, m)
+		}
+	}
+	return ans
+}
+
+// Walk through exported package level declarations (including methods of exported types)
+func (imp *Import) ExportedSymbols(walk func(sym *Symbol) error) error {
+	return imp.Symbols(func(sym *Symbol) error {
+		if !sym.Exported() {
+			return nil
+		}
+		return walk(sym)
+	})
+}
+
+// Exported API of scanned import: package level declarations and methods of exported types sorted by names
+// (methods are sorted as Type.Method).
+func (prj *Project) ExportedAPI(importPath string) ([]*Symbol, error) {
+	imp := prj.Imports.ByImport(importPath)
+	if imp == nil {
+		return nil, errors.Wrap(ErrImportNotFound, importPath)
+	}
+	var ans []*Symbol
+	err := imp.ExportedSymbols(func(sym *Symbol) error {
+		ans = append(ans, sym)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(ans, func(i, j int) bool {
+		return apiName(ans[i]) < apiName(ans[j])
+	})
+	return ans, nil
+}
+
+func apiName(sym *Symbol) string {
+	if fn, ok := sym.Node.(*ast.FuncDecl); ok && fn.Recv != nil {
+		recv, _ := receiverType(fn)
+		return recv + "." + sym.Name
+	}
+	return sym.Name
+}
@@ -0,0 +1,255 @@
+package symbols
+
+import (
+	"github.com/pkg/errors"
+	"go/ast"
+	"go/types"
+	"strings"
+)
+
+// Way to check that value is the zero value of type
+type ZeroCheck int
+
+const (
+	ZeroCompare  ZeroCheck = iota + 1 // x == zero value: basic types, comparable structs, arrays and type parameters
+	ZeroNil                           // x == nil: pointers, functions, channels and interfaces
+	ZeroLen                           // len(x) == 0: slices and maps (nil and empty values are not distinguished)
+	ZeroFields                        // all fields are zero: structs with not comparable fields
+	ZeroElements                      // all elements are zero: arrays with not comparable elements
+)
+
+func (zc ZeroCheck) String() string {
+	switch zc {
+	case ZeroCompare:
+		return "compare"
+	case ZeroNil:
+		return "nil"
+	case ZeroLen:
+		return "len"
+	case ZeroFields:
+		return "fields"
+	case ZeroElements:
+		return "elements"
+	}
+	return ""
+}
+
+// Zero value of type
+type Zero struct {
+	Type       *Symbol      // analyzed type
+	Underlying *Symbol      // built-in type, type literal or type parameter
+	Comparable bool         // values can be compared by ==
+	Check      ZeroCheck    // how to check that value is zero
+	Value      string       // zero value expression: nil, 0, "", false, T{} or *new(T)
+	Fields     []*ZeroField // zero values of fields for ZeroFields check
+	Elem       *Zero        // zero value of element for ZeroElements check
+}
+
+type ZeroField struct {
+	Name string // field name or type name for embedded fields
+	Zero *Zero
+}
+
+// Check that values of type (declaration or type expression) can be compared by == and !=
+func (sym *Symbol) Comparable(resolver Resolver) (bool, error) {
+	return isComparable(resolver, sym, 0)
+}
+
+func isComparable(resolver Resolver, sym *Symbol, depth int) (bool, error) {
+	if depth > maxResolveDepth {
+		return false, errors.Errorf("too deep type %v", sym.Name)
+	}
+	underlying, err := sym.Underlying(resolver)
+	if err != nil {
+		return false, err
+	}
+	if underlying.TypeParam {
+		return comparableConstraint(resolver, underlying.Constraint(), depth+1)
+	}
+	if underlying.BuiltIn {
+		return underlying.Universe == UniverseType, nil
+	}
+	switch v := underlying.Node.(type) {
+	case *ast.StarExpr, *ast.ChanType, *ast.InterfaceType:
+		return true, nil
+	case *ast.MapType, *ast.FuncType, *ast.Ellipsis:
+		return false, nil
+	case *ast.ArrayType:
+		if v.Len == nil {
+			return false, nil
+		}
+		return isComparable(resolver, underlying.TypeExpr(v.Elt), depth+1)
+	case *ast.StructType:
+		for _, f := range flatFields(v.Fields) {
+			if ok, err := isComparable(resolver, underlying.TypeExpr(f.field.Type), depth+1); !ok || err != nil {
+				return ok, err
+			}
+		}
+		return true, nil
+	}
+	return false, unsupportedType(underlying)
+}
+
+// constraint requires comparable types: embeds comparable or type set contains only comparable types
+func comparableConstraint(resolver Resolver, constraint *Symbol, depth int) (bool, error) {
+	if constraint == nil {
+		return false, nil
+	}
+	underlying, err := constraint.Underlying(resolver)
+	if err != nil {
+		return false, err
+	}
+	if underlying.BuiltIn {
+		return underlying.Name == "comparable", nil
+	}
+	iface, ok := underlying.Node.(*ast.InterfaceType)
+	if !ok {
+		// type set of single type or union
+		if expr, ok := underlying.Node.(ast.Expr); ok {
+			return comparableTerms(resolver, underlying, expr, depth)
+		}
+		return false, nil
+	}
+	for _, m := range iface.Methods.List {
+		if len(m.Names) > 0 {
+			continue
+		}
+		if ok, err := comparableTerms(resolver, underlying, m.Type, depth); ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+func comparableTerms(resolver Resolver, scope *Symbol, expr ast.Expr, depth int) (bool, error) {
+	switch v := unparen(expr).(type) {
+	case *ast.BinaryExpr:
+		if ok, err := comparableTerms(resolver, scope, v.X, depth); !ok || err != nil {
+			return ok, err
+		}
+		return comparableTerms(resolver, scope, v.Y, depth)
+	case *ast.UnaryExpr:
+		return isComparable(resolver, scope.TypeExpr(v.X), depth+1)
+	}
+	tp := scope.TypeExpr(expr)
+	underlying, err := tp.Underlying(resolver)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := underlying.Node.(*ast.InterfaceType); ok || underlying.BuiltIn && !underlying.TypeParam && underlying.Basic == types.Invalid {
+		// embedded constraint
+		return comparableConstraint(resolver, tp, depth+1)
+	}
+	return isComparable(resolver, tp, depth+1)
+}
+
+// Zero value of type (declaration or type expression) and how to check that value is zero without reflection
+func (sym *Symbol) Zero(resolver Resolver) (*Zero, error) {
+	return zeroOf(resolver, sym, 0)
+}
+
+func zeroOf(resolver Resolver, sym *Symbol, depth int) (*Zero, error) {
+	if depth > maxResolveDepth {
+		return nil, errors.Errorf("too deep type %v", sym.Name)
+	}
+	underlying, err := sym.Underlying(resolver)
+	if err != nil {
+		return nil, err
+	}
+	ok, err := isComparable(resolver, underlying, depth)
+	if err != nil {
+		return nil, err
+	}
+	zero := &Zero{Type: sym, Underlying: underlying, Comparable: ok}
+	if underlying.TypeParam {
+		if !ok {
+			return nil, errors.Errorf("zero value of type parameter %v can't be checked without comparable constraint", underlying.Name)
+		}
+		zero.Check, zero.Value = ZeroCompare, "*new("+typeString(sym)+")"
+		return zero, nil
+	}
+	if underlying.BuiltIn {
+		info := types.Typ[underlying.Basic].Info()
+		switch {
+		case underlying.Universe != UniverseType:
+			return nil, errors.Errorf("%v is not a type", underlying.Name)
+		case info&types.IsBoolean != 0:
+			zero.Check, zero.Value = ZeroCompare, "false"
+		case info&types.IsString != 0:
+			zero.Check, zero.Value = ZeroCompare, `""`
+		case info&types.IsNumeric != 0:
+			zero.Check, zero.Value = ZeroCompare, "0"
+		default:
+			// error, any
+			zero.Check, zero.Value = ZeroNil, "nil"
+		}
+		return zero, nil
+	}
+	switch v := underlying.Node.(type) {
+	case *ast.StarExpr, *ast.ChanType, *ast.FuncType, *ast.InterfaceType:
+		zero.Check, zero.Value = ZeroNil, "nil"
+		return zero, nil
+	case *ast.MapType:
+		zero.Check, zero.Value = ZeroLen, "nil"
+		return zero, nil
+	case *ast.ArrayType:
+		if v.Len == nil {
+			zero.Check, zero.Value = ZeroLen, "nil"
+			return zero, nil
+		}
+		zero.Value = typeString(sym) + "{}"
+		if ok {
+			zero.Check = ZeroCompare
+			return zero, nil
+		}
+		zero.Check = ZeroElements
+		zero.Elem, err = zeroOf(resolver, underlying.TypeExpr(v.Elt), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return zero, nil
+	case *ast.StructType:
+		zero.Value = typeString(sym) + "{}"
+		if ok {
+			zero.Check = ZeroCompare
+			return zero, nil
+		}
+		zero.Check = ZeroFields
+		for _, f := range flatFields(v.Fields) {
+			name := f.name
+			if name == "" {
+				name = embeddedName(f.field)
+			}
+			if name == "_" {
+				continue
+			}
+			fieldZero, err := zeroOf(resolver, underlying.TypeExpr(f.field.Type), depth+1)
+			if err != nil {
+				return nil, errors.Wrapf(err, "field %v", name)
+			}
+			zero.Fields = append(zero.Fields, &ZeroField{Name: name, Zero: fieldZero})
+		}
+		return zero, nil
+	}
+	return nil, unsupportedType(underlying)
+}
+
+// type as it's written in source
+func typeString(sym *Symbol) string {
+	if _, ok := sym.Node.(*ast.TypeSpec); !ok || len(sym.TypeArgs) == 0 {
+		if expr, ok := sym.Node.(ast.Expr); ok && !sym.TypeParam {
+			return types.ExprString(expr)
+		}
+		return sym.Name
+	}
+	var args []string
+	for _, arg := range sym.TypeArgs {
+		args = append(args, typeString(arg))
+	}
+	return sym.Name + "[" + strings.Join(args, ", ") + "]"
+}
+
+func unsupportedType(sym *Symbol) error {
+	expr, _ := sym.Node.(ast.Expr)
+	return &UnsupportedTypeError{Expr: expr, Pos: sym.Position()}
+}